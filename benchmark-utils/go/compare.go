@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jimsmart/grobotstxt"
+	robotstxt "github.com/nzrsky/robotstxt/bindings/go"
+	temoto "github.com/temoto/robotstxt"
+)
+
+// impl is one robots.txt implementation under comparison. allowed reports
+// the verdict for agent on the URL path of example.com.
+type impl struct {
+	name    string
+	allowed func(content, agent, path string) bool
+}
+
+// implementations returns the implementations under comparison and a func
+// that releases their resources.
+func implementations() ([]impl, func()) {
+	m := robotstxt.NewMatcher()
+	return []impl{
+		{"nzrsky/robotstxt (cgo)", func(content, agent, path string) bool {
+			return m.IsAllowed(content, agent, "https://example.com"+path)
+		}},
+		{"jimsmart/grobotstxt", func(content, agent, path string) bool {
+			return grobotstxt.AgentAllowed(content, agent, "https://example.com"+path)
+		}},
+		{"temoto/robotstxt", func(content, agent, path string) bool {
+			data, err := temoto.FromString(content)
+			if err != nil {
+				return true
+			}
+			return data.TestAgent(path, agent)
+		}},
+	}, m.Free
+}
+
+// comparePaths are checked against every file; verdicts that differ from
+// the first implementation count as divergences.
+var comparePaths = []string{"/", "/search?q=x", "/admin/", "/private/file.pdf", "/a/b/c.html"}
+
+type result struct {
+	name       string
+	elapsed    time.Duration
+	files      int
+	bytes      int64
+	allocs     uint64
+	allocBytes uint64
+	diverging  int
+}
+
+// compare runs every implementation over files passes times, checking each
+// file against every comparePaths entry, and returns one result per
+// implementation. Per-file figures thus cover len(comparePaths) checks.
+func compare(files []string, agent string, passes int) []result {
+	var total int64
+	for _, f := range files {
+		total += int64(len(f))
+	}
+	impls, done := implementations()
+	defer done()
+
+	// Reference verdicts, for divergence counts.
+	ref := make([][]bool, len(files))
+	for i, f := range files {
+		for _, p := range comparePaths {
+			ref[i] = append(ref[i], impls[0].allowed(f, agent, p))
+		}
+	}
+
+	var results []result
+	for _, im := range impls {
+		r := result{name: im.name, files: len(files) * passes, bytes: total * int64(passes)}
+		for i, f := range files {
+			for j, p := range comparePaths {
+				if im.allowed(f, agent, p) != ref[i][j] {
+					r.diverging++
+					break
+				}
+			}
+		}
+
+		runtime.GC()
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+		for n := 0; n < passes; n++ {
+			for _, f := range files {
+				for _, p := range comparePaths {
+					im.allowed(f, agent, p)
+				}
+			}
+		}
+		r.elapsed = time.Since(start)
+		runtime.ReadMemStats(&after)
+		r.allocs = after.Mallocs - before.Mallocs
+		r.allocBytes = after.TotalAlloc - before.TotalAlloc
+		results = append(results, r)
+	}
+	return results
+}
+
+func (r result) filesPerSec() float64 { return float64(r.files) / r.elapsed.Seconds() }
+func (r result) mbPerSec() float64    { return float64(r.bytes) / r.elapsed.Seconds() / 1e6 }
+func (r result) allocsPerFile() float64 {
+	return float64(r.allocs) / float64(r.files)
+}
+func (r result) bytesPerFile() float64 {
+	return float64(r.allocBytes) / float64(r.files)
+}
+
+// reportColumns heads the report. The allocation columns come from
+// runtime.MemStats and so miss the C++ heap behind the cgo binding.
+var reportColumns = []string{"Implementation", "Files/s", "MB/s", "Go allocs/file", "Go bytes/file", "Diverging files"}
+
+// heapNote qualifies the allocation columns in the report.
+const heapNote = "Allocation columns count the Go heap only; the C++ matcher behind cgo allocates outside it."
+
+func (r result) row(corpus int) []string {
+	return []string{
+		r.name,
+		fmt.Sprintf("%.0f", r.filesPerSec()),
+		fmt.Sprintf("%.1f", r.mbPerSec()),
+		fmt.Sprintf("%.1f", r.allocsPerFile()),
+		fmt.Sprintf("%.0f", r.bytesPerFile()),
+		fmt.Sprintf("%d / %d", r.diverging, corpus),
+	}
+}
+
+// writeMarkdown writes the comparison as a markdown table.
+func writeMarkdown(w io.Writer, results []result, corpus int, agent string) {
+	fmt.Fprintf(w, "# robots.txt parser comparison\n\n")
+	fmt.Fprintf(w, "%d files, user-agent %q, divergence against %s over %d paths. %s\n\n",
+		corpus, agent, results[0].name, len(comparePaths), heapNote)
+	fmt.Fprintf(w, "| %s |\n", strings.Join(reportColumns, " | "))
+	fmt.Fprintf(w, "|%s\n", strings.Repeat("---|", len(reportColumns)))
+	for _, r := range results {
+		fmt.Fprintf(w, "| %s |\n", strings.Join(r.row(corpus), " | "))
+	}
+}
+
+// writeHTML writes the comparison as a standalone HTML page.
+func writeHTML(w io.Writer, results []result, corpus int, agent string) {
+	fmt.Fprintf(w, "<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"><title>robots.txt parser comparison</title></head><body>\n")
+	fmt.Fprintf(w, "<h1>robots.txt parser comparison</h1>\n<p>%d files, user-agent %s, divergence against %s over %d paths. %s</p>\n",
+		corpus, html.EscapeString(agent), html.EscapeString(results[0].name), len(comparePaths), heapNote)
+	fmt.Fprintf(w, "<table>\n<tr>")
+	for _, c := range reportColumns {
+		fmt.Fprintf(w, "<th>%s</th>", c)
+	}
+	fmt.Fprintf(w, "</tr>\n")
+	for _, r := range results {
+		fmt.Fprintf(w, "<tr>")
+		for _, c := range r.row(corpus) {
+			fmt.Fprintf(w, "<td>%s</td>", html.EscapeString(c))
+		}
+		fmt.Fprintf(w, "</tr>\n")
+	}
+	fmt.Fprintf(w, "</table>\n</body></html>\n")
+}
@@ -2,4 +2,10 @@ module go-bench
 
 go 1.21
 
-require github.com/jimsmart/grobotstxt v1.0.3
+require (
+	github.com/jimsmart/grobotstxt v1.0.3
+	github.com/nzrsky/robotstxt/bindings/go v0.0.0
+	github.com/temoto/robotstxt v1.1.2
+)
+
+replace github.com/nzrsky/robotstxt/bindings/go => ../../bindings/go
@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/binary"
+	"flag"
 	"fmt"
 	"io"
 	"os"
@@ -38,21 +39,38 @@ func loadRobotsFiles(filename string) ([]string, error) {
 }
 
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Fprintln(os.Stderr, "Usage: go-bench <robots_all.bin>")
+	report := flag.String("compare", "", "compare implementations and write a report: md or html")
+	passes := flag.Int("passes", 3, "corpus passes per implementation with -compare")
+	agent := flag.String("agent", "Googlebot", "user-agent to check")
+	flag.Parse()
+	if flag.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: go-bench [-compare md|html] [-passes N] [-agent NAME] <robots_all.bin>")
 		os.Exit(1)
 	}
 
-	files, err := loadRobotsFiles(os.Args[1])
+	files, err := loadRobotsFiles(flag.Arg(0))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading files: %v\n", err)
 		os.Exit(1)
 	}
 
+	switch *report {
+	case "":
+	case "md":
+		writeMarkdown(os.Stdout, compare(files, *agent, *passes), len(files), *agent)
+		return
+	case "html":
+		writeHTML(os.Stdout, compare(files, *agent, *passes), len(files), *agent)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown report format %q\n", *report)
+		os.Exit(1)
+	}
+
 	// Parse and match all files
 	allowed := 0
 	for _, content := range files {
-		if grobotstxt.AgentAllowed(content, "Googlebot", "/") {
+		if grobotstxt.AgentAllowed(content, *agent, "/") {
 			allowed++
 		}
 	}
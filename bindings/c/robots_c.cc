@@ -27,12 +27,76 @@
 
 #define ROBOTS_VERSION "1.1.0"
 
+// Source commit, set by the build (e.g. -DROBOTS_COMMIT="\"abc123\"").
+#ifndef ROBOTS_COMMIT
+#define ROBOTS_COMMIT ""
+#endif
+
+#define ROBOTS_STR_(x) #x
+#define ROBOTS_STR(x) ROBOTS_STR_(x)
+
+#if defined(__clang__)
+#define ROBOTS_COMPILER "clang " __clang_version__
+#elif defined(__GNUC__)
+#define ROBOTS_COMPILER "gcc " __VERSION__
+#elif defined(_MSC_VER)
+#define ROBOTS_COMPILER "msvc " ROBOTS_STR(_MSC_FULL_VER)
+#else
+#define ROBOTS_COMPILER "unknown"
+#endif
+
 // =============================================================================
 // Internal wrapper struct
 // =============================================================================
 
+// RobotsMatcher that keeps the pattern of each rule that became the best
+// match while matching, so the winning rule can be reported afterwards.
+class RuleRecordingMatcher : public googlebot::RobotsMatcher {
+ public:
+  struct Rule {
+    int line;
+    robots_directive_type_t type;
+    std::string pattern;
+  };
+
+  // Returns the rule on matching_line(), or nullptr if none matched.
+  const Rule* matched_rule() const {
+    int line = matching_line();
+    for (const auto& r : rules_) {
+      if (r.line == line) return &r;
+    }
+    return nullptr;
+  }
+
+ protected:
+  void HandleRobotsStart() override {
+    rules_.clear();
+    googlebot::RobotsMatcher::HandleRobotsStart();
+  }
+  void HandleAllow(int line_num, std::string_view value) override {
+    googlebot::RobotsMatcher::HandleAllow(line_num, value);
+    Record(line_num, ROBOTS_DIRECTIVE_ALLOW, value);
+  }
+  void HandleDisallow(int line_num, std::string_view value) override {
+    googlebot::RobotsMatcher::HandleDisallow(line_num, value);
+    Record(line_num, ROBOTS_DIRECTIVE_DISALLOW, value);
+  }
+
+ private:
+  // Only rules that just became the best match are kept; the final match
+  // is always among them.
+  void Record(int line_num, robots_directive_type_t type,
+              std::string_view value) {
+    if (matching_line() == line_num) {
+      rules_.push_back({line_num, type, std::string(value)});
+    }
+  }
+
+  std::vector<Rule> rules_;
+};
+
 struct robots_matcher_s {
-  googlebot::RobotsMatcher matcher;
+  RuleRecordingMatcher matcher;
 };
 
 // =============================================================================
@@ -51,6 +115,13 @@ extern "C" void robots_matcher_free(robots_matcher_t* matcher) {
   delete matcher;
 }
 
+extern "C" void robots_matcher_reset(robots_matcher_t* matcher) {
+  if (!matcher) return;
+  // Matching against an empty robots.txt runs HandleRobotsStart(), which
+  // clears all per-match state.
+  matcher->matcher.OneAgentAllowedByRobots("", "", "/");
+}
+
 // =============================================================================
 // URL checking
 // =============================================================================
@@ -92,6 +163,33 @@ extern "C" bool robots_allowed_by_robots_multi(
   return matcher->matcher.AllowedByRobots(robots_body, &agents, target_url);
 }
 
+extern "C" bool robots_check(
+    robots_matcher_t* matcher,
+    const char* robots_txt, size_t robots_txt_len,
+    const char* user_agent, size_t user_agent_len,
+    const char* url, size_t url_len,
+    robots_check_result_t* result) {
+  bool allowed = robots_allowed_by_robots(matcher, robots_txt, robots_txt_len,
+                                          user_agent, user_agent_len,
+                                          url, url_len);
+  if (!result) return allowed;
+
+  result->allowed = allowed;
+  result->matching_line = robots_matching_line(matcher);
+  result->ever_seen_specific_agent = robots_ever_seen_specific_agent(matcher);
+  result->has_crawl_delay = robots_has_crawl_delay(matcher);
+  result->crawl_delay = robots_get_crawl_delay(matcher);
+  result->crawl_delay_source = robots_crawl_delay_source(matcher);
+  result->crawl_delay_line = robots_crawl_delay_line(matcher);
+  result->has_request_rate =
+      robots_get_request_rate(matcher, &result->request_rate);
+  result->has_request_rate_window =
+      robots_get_request_rate_window(matcher, &result->request_rate_window);
+  result->has_content_signal =
+      robots_get_content_signal(matcher, &result->content_signal);
+  return allowed;
+}
+
 // =============================================================================
 // Matcher state accessors
 // =============================================================================
@@ -121,6 +219,24 @@ extern "C" double robots_get_crawl_delay(const robots_matcher_t* matcher) {
   return delay.value_or(0.0);
 }
 
+extern "C" robots_group_source_t robots_crawl_delay_source(
+    const robots_matcher_t* matcher) {
+  if (!matcher) return ROBOTS_GROUP_NONE;
+  switch (matcher->matcher.GetCrawlDelaySource()) {
+    case googlebot::RobotsMatcher::GroupSource::kSpecific:
+      return ROBOTS_GROUP_SPECIFIC;
+    case googlebot::RobotsMatcher::GroupSource::kGlobal:
+      return ROBOTS_GROUP_GLOBAL;
+    default:
+      return ROBOTS_GROUP_NONE;
+  }
+}
+
+extern "C" int robots_crawl_delay_line(const robots_matcher_t* matcher) {
+  if (!matcher) return 0;
+  return matcher->matcher.GetCrawlDelayLine();
+}
+
 // =============================================================================
 // Request-rate support
 // =============================================================================
@@ -140,6 +256,16 @@ extern "C" bool robots_get_request_rate(const robots_matcher_t* matcher,
   return true;
 }
 
+extern "C" bool robots_get_request_rate_window(const robots_matcher_t* matcher,
+                                               robots_time_window_t* window) {
+  if (!matcher || !window) return false;
+  auto opt_rate = matcher->matcher.GetRequestRate();
+  if (!opt_rate.has_value() || !opt_rate->HasWindow()) return false;
+  window->start_minute = opt_rate->window_start;
+  window->end_minute = opt_rate->window_end;
+  return true;
+}
+
 // =============================================================================
 // Content-Signal support
 // =============================================================================
@@ -222,6 +348,132 @@ extern "C" bool robots_allows_search(const robots_matcher_t* matcher) {
 #endif
 }
 
+// =============================================================================
+// Parsing
+// =============================================================================
+
+struct robots_parsed_s {
+  struct Entry {
+    int line;
+    robots_directive_type_t type;
+    std::string key;
+    std::string value;
+  };
+  std::vector<Entry> entries;
+  std::vector<robots_directive_t> directives;
+  robots_parse_info_t info = {0, 0};
+};
+
+namespace {
+
+// Records every directive reported by the parser.
+class DirectiveCollector : public googlebot::RobotsParseHandler {
+ public:
+  explicit DirectiveCollector(robots_parsed_t* parsed) : parsed_(parsed) {}
+
+  void HandleRobotsStart() override {}
+  void HandleRobotsEnd() override {}
+  void HandleUserAgent(int line_num, std::string_view value) override {
+    Add(line_num, ROBOTS_DIRECTIVE_USER_AGENT, {}, value);
+  }
+  void HandleAllow(int line_num, std::string_view value) override {
+    Add(line_num, ROBOTS_DIRECTIVE_ALLOW, {}, value);
+  }
+  void HandleDisallow(int line_num, std::string_view value) override {
+    Add(line_num, ROBOTS_DIRECTIVE_DISALLOW, {}, value);
+  }
+  void HandleSitemap(int line_num, std::string_view value) override {
+    Add(line_num, ROBOTS_DIRECTIVE_SITEMAP, {}, value);
+  }
+  void HandleCrawlDelay(int line_num, double) override {
+    Add(line_num, ROBOTS_DIRECTIVE_CRAWL_DELAY, {}, {});
+  }
+  void HandleRequestRate(int line_num, const googlebot::RequestRate&) override {
+    Add(line_num, ROBOTS_DIRECTIVE_REQUEST_RATE, {}, {});
+  }
+#if ROBOTS_SUPPORT_CONTENT_SIGNAL
+  void HandleContentSignal(int line_num,
+                           const googlebot::ContentSignal&) override {
+    Add(line_num, ROBOTS_DIRECTIVE_CONTENT_SIGNAL, {}, {});
+  }
+#endif  // ROBOTS_SUPPORT_CONTENT_SIGNAL
+  void HandleUnknownAction(int line_num, std::string_view action,
+                           std::string_view value) override {
+    Add(line_num, ROBOTS_DIRECTIVE_UNKNOWN, action, value);
+  }
+  void ReportLineMetadata(int line_num, const LineMetadata& metadata) override {
+    if (line_num > parsed_->info.lines) parsed_->info.lines = line_num;
+    if (metadata.is_line_too_long) ++parsed_->info.long_lines;
+  }
+
+ private:
+  void Add(int line_num, robots_directive_type_t type, std::string_view key,
+           std::string_view value) {
+    parsed_->entries.push_back(
+        {line_num, type, std::string(key), std::string(value)});
+  }
+
+  robots_parsed_t* parsed_;
+};
+
+}  // namespace
+
+extern "C" robots_parsed_t* robots_parse(const char* robots_txt,
+                                         size_t robots_txt_len) {
+  try {
+    auto* parsed = new robots_parsed_t();
+    DirectiveCollector collector(parsed);
+    googlebot::ParseRobotsTxt(
+        robots_txt ? std::string_view(robots_txt, robots_txt_len)
+                   : std::string_view(),
+        &collector);
+    // Entries no longer move, so their strings can be referenced directly.
+    parsed->directives.reserve(parsed->entries.size());
+    for (const auto& e : parsed->entries) {
+      parsed->directives.push_back({e.line, e.type, e.key.data(), e.key.size(),
+                                    e.value.data(), e.value.size()});
+    }
+    return parsed;
+  } catch (...) {
+    return nullptr;
+  }
+}
+
+extern "C" void robots_parsed_free(robots_parsed_t* parsed) {
+  delete parsed;
+}
+
+extern "C" void robots_parsed_info(const robots_parsed_t* parsed,
+                                   robots_parse_info_t* info) {
+  if (!info) return;
+  *info = parsed ? parsed->info : robots_parse_info_t{0, 0};
+}
+
+extern "C" size_t robots_parsed_count(const robots_parsed_t* parsed) {
+  return parsed ? parsed->directives.size() : 0;
+}
+
+extern "C" const robots_directive_t* robots_parsed_directives(
+    const robots_parsed_t* parsed) {
+  return parsed ? parsed->directives.data() : nullptr;
+}
+
+// =============================================================================
+// Matched rule
+// =============================================================================
+
+extern "C" bool robots_matched_rule(const robots_matcher_t* matcher,
+                                    robots_matched_rule_t* rule) {
+  if (!matcher || !rule) return false;
+  const auto* r = matcher->matcher.matched_rule();
+  if (!r) return false;
+  rule->line = r->line;
+  rule->type = r->type;
+  rule->pattern = r->pattern.data();
+  rule->pattern_len = r->pattern.size();
+  return true;
+}
+
 // =============================================================================
 // Utility functions
 // =============================================================================
@@ -235,3 +487,21 @@ extern "C" bool robots_is_valid_user_agent(const char* user_agent, size_t len) {
 extern "C" const char* robots_version(void) {
   return ROBOTS_VERSION;
 }
+
+extern "C" int robots_abi_version(void) {
+  return ROBOTS_ABI_VERSION;
+}
+
+extern "C" void robots_build_info(robots_build_info_t* info) {
+  if (!info) return;
+  info->version = ROBOTS_VERSION;
+  info->commit = ROBOTS_COMMIT;
+  info->compiler = ROBOTS_COMPILER;
+  info->cplusplus = __cplusplus;
+  info->content_signal = ROBOTS_SUPPORT_CONTENT_SIGNAL != 0;
+#ifdef ROBOTS_USE_ADA
+  info->ada = true;
+#else
+  info->ada = false;
+#endif
+}
@@ -71,6 +71,13 @@ typedef struct {
   int seconds;   // Time period in seconds
 } robots_request_rate_t;
 
+// Time-of-day visit window from "Request-rate: 1/10 0600-1400", in minutes
+// after midnight UTC. end_minute < start_minute wraps past midnight.
+typedef struct {
+  int start_minute;
+  int end_minute;
+} robots_time_window_t;
+
 // Content-Signal values for AI content preferences.
 // Each field uses a tri-state: -1 = not set, 0 = no, 1 = yes.
 typedef struct {
@@ -79,6 +86,30 @@ typedef struct {
   int8_t search;    // search: Building search indexes and providing results
 } robots_content_signal_t;
 
+// User-agent group a per-group value (e.g. crawl-delay) was taken from.
+typedef enum {
+  ROBOTS_GROUP_NONE = 0,      // Value not specified
+  ROBOTS_GROUP_SPECIFIC = 1,  // Group matching the user-agent
+  ROBOTS_GROUP_GLOBAL = 2,    // Global (*) group
+} robots_group_source_t;
+
+// Result of robots_check(): the verdict together with all per-match state.
+typedef struct {
+  bool allowed;
+  int matching_line;                       // 0 if no rule matched
+  bool ever_seen_specific_agent;
+  bool has_crawl_delay;
+  double crawl_delay;                      // Seconds, valid if has_crawl_delay
+  robots_group_source_t crawl_delay_source;
+  int crawl_delay_line;
+  bool has_request_rate;
+  robots_request_rate_t request_rate;      // Valid if has_request_rate
+  bool has_request_rate_window;
+  robots_time_window_t request_rate_window;  // Valid if has_request_rate_window
+  bool has_content_signal;
+  robots_content_signal_t content_signal;  // Valid if has_content_signal
+} robots_check_result_t;
+
 // =============================================================================
 // Matcher lifecycle
 // =============================================================================
@@ -92,6 +123,11 @@ ROBOTS_API robots_matcher_t* robots_matcher_create(void);
 // Safe to call with NULL.
 ROBOTS_API void robots_matcher_free(robots_matcher_t* matcher);
 
+// Clears all per-match state (matching line, crawl-delay, request-rate and
+// content-signal) so the matcher behaves as if freshly created.
+// Safe to call with NULL.
+ROBOTS_API void robots_matcher_reset(robots_matcher_t* matcher);
+
 // =============================================================================
 // URL checking
 // =============================================================================
@@ -135,6 +171,18 @@ ROBOTS_API bool robots_allowed_by_robots_multi(
     size_t num_user_agents,
     const char* url, size_t url_len);
 
+// Checks if a URL is allowed for a single user-agent and fills in all
+// per-match state, replacing robots_allowed_by_robots() followed by the
+// individual accessors below.
+//
+// Returns true if the URL is allowed, false if disallowed. result may be NULL.
+ROBOTS_API bool robots_check(
+    robots_matcher_t* matcher,
+    const char* robots_txt, size_t robots_txt_len,
+    const char* user_agent, size_t user_agent_len,
+    const char* url, size_t url_len,
+    robots_check_result_t* result);
+
 // =============================================================================
 // Matcher state accessors (call after robots_allowed_by_robots)
 // =============================================================================
@@ -156,6 +204,16 @@ ROBOTS_API bool robots_has_crawl_delay(const robots_matcher_t* matcher);
 // Call robots_has_crawl_delay() first to distinguish "not set" from "0".
 ROBOTS_API double robots_get_crawl_delay(const robots_matcher_t* matcher);
 
+// Returns the group that supplied the crawl-delay. ROBOTS_GROUP_GLOBAL with
+// robots_ever_seen_specific_agent() means the matched group had no
+// crawl-delay and the global (*) group's value was used instead.
+ROBOTS_API robots_group_source_t robots_crawl_delay_source(
+    const robots_matcher_t* matcher);
+
+// Returns the line number of the crawl-delay line that supplied the value, or
+// 0 if none was specified.
+ROBOTS_API int robots_crawl_delay_line(const robots_matcher_t* matcher);
+
 // =============================================================================
 // Request-rate support (non-standard directive)
 // =============================================================================
@@ -168,6 +226,11 @@ ROBOTS_API bool robots_has_request_rate(const robots_matcher_t* matcher);
 ROBOTS_API bool robots_get_request_rate(const robots_matcher_t* matcher,
                                          robots_request_rate_t* rate);
 
+// Gets the visit window of the request-rate, if it carried one. Returns false
+// if no request-rate or no window was specified.
+ROBOTS_API bool robots_get_request_rate_window(const robots_matcher_t* matcher,
+                                         robots_time_window_t* window);
+
 // =============================================================================
 // Content-Signal support (proposed AI directive)
 // =============================================================================
@@ -189,6 +252,83 @@ ROBOTS_API bool robots_allows_ai_train(const robots_matcher_t* matcher);
 ROBOTS_API bool robots_allows_ai_input(const robots_matcher_t* matcher);
 ROBOTS_API bool robots_allows_search(const robots_matcher_t* matcher);
 
+// =============================================================================
+// Parsing
+// =============================================================================
+
+// Directive types reported by robots_parse().
+typedef enum {
+  ROBOTS_DIRECTIVE_UNKNOWN = 0,
+  ROBOTS_DIRECTIVE_USER_AGENT = 1,
+  ROBOTS_DIRECTIVE_ALLOW = 2,
+  ROBOTS_DIRECTIVE_DISALLOW = 3,
+  ROBOTS_DIRECTIVE_SITEMAP = 4,
+  ROBOTS_DIRECTIVE_CRAWL_DELAY = 5,
+  ROBOTS_DIRECTIVE_REQUEST_RATE = 6,
+  ROBOTS_DIRECTIVE_CONTENT_SIGNAL = 7
+} robots_directive_type_t;
+
+// One directive line. Strings are not null-terminated and remain valid until
+// robots_parsed_free(). key is only set for ROBOTS_DIRECTIVE_UNKNOWN; value
+// is empty for crawl-delay, request-rate and content-signal, which the parser
+// reports pre-parsed (use the matcher accessors for those).
+typedef struct {
+  int line;
+  robots_directive_type_t type;
+  const char* key;
+  size_t key_len;
+  const char* value;
+  size_t value_len;
+} robots_directive_t;
+
+// Line statistics of a parse result.
+typedef struct {
+  int lines;           // Number of lines, as numbered by the parser
+  int long_lines;      // Lines over the parser's length limit, whose tail
+                       // was ignored
+} robots_parse_info_t;
+
+// Opaque parse result.
+typedef struct robots_parsed_s robots_parsed_t;
+
+// Parses robots.txt and records its directives in file order, with the same
+// key recognition (including accepted typos) as the matcher. Returns NULL on
+// allocation failure. The result must be freed with robots_parsed_free().
+ROBOTS_API robots_parsed_t* robots_parse(const char* robots_txt, size_t robots_txt_len);
+
+// Frees a parse result. Safe to call with NULL.
+ROBOTS_API void robots_parsed_free(robots_parsed_t* parsed);
+
+// Fills in line statistics for a parse result.
+ROBOTS_API void robots_parsed_info(const robots_parsed_t* parsed,
+                             robots_parse_info_t* info);
+
+// Returns the number of directives in a parse result.
+ROBOTS_API size_t robots_parsed_count(const robots_parsed_t* parsed);
+
+// Returns the directives of a parse result (robots_parsed_count() entries).
+ROBOTS_API const robots_directive_t* robots_parsed_directives(
+    const robots_parsed_t* parsed);
+
+// =============================================================================
+// Matched rule
+// =============================================================================
+
+// The allow or disallow rule that decided the most recent match.
+typedef struct {
+  int line;
+  robots_directive_type_t type;  // ROBOTS_DIRECTIVE_ALLOW or _DISALLOW
+  const char* pattern;           // Not null-terminated; valid until the
+  size_t pattern_len;            // next match or robots_matcher_free()
+} robots_matched_rule_t;
+
+// Fills in the rule on robots_matching_line(), with its pattern as the
+// matcher saw it (percent-escaped), so callers need not keep the robots.txt
+// body to explain a verdict. Returns false and leaves rule untouched if no
+// rule matched.
+ROBOTS_API bool robots_matched_rule(const robots_matcher_t* matcher,
+                             robots_matched_rule_t* rule);
+
 // =============================================================================
 // Utility functions
 // =============================================================================
@@ -199,6 +339,29 @@ ROBOTS_API bool robots_is_valid_user_agent(const char* user_agent, size_t len);
 // Returns the library version string.
 ROBOTS_API const char* robots_version(void);
 
+// Version of the C ABI: the layout of the structs and the signatures of the
+// functions in this header. It changes whenever either changes
+// incompatibly, so bindings can refuse a mismatched shared library.
+#define ROBOTS_ABI_VERSION 1
+
+// Returns the ABI version the library was built with (ROBOTS_ABI_VERSION
+// of its header). Compare it with ROBOTS_ABI_VERSION before other calls.
+ROBOTS_API int robots_abi_version(void);
+
+// How the library was compiled, for bug reports and cache compatibility
+// checks. Strings are static and never NULL.
+typedef struct {
+  const char* version;   // Same as robots_version()
+  const char* commit;    // Source commit (ROBOTS_COMMIT), or "" if unknown
+  const char* compiler;  // Compiler name and version
+  long cplusplus;        // C++ standard (__cplusplus) the library was built with
+  bool content_signal;   // Built with ROBOTS_SUPPORT_CONTENT_SIGNAL
+  bool ada;              // URLs parsed with the ada library (ROBOTS_USE_ADA)
+} robots_build_info_t;
+
+// Fills in how the library was built.
+ROBOTS_API void robots_build_info(robots_build_info_t* info);
+
 #ifdef __cplusplus
 }
 #endif
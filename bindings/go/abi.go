@@ -0,0 +1,43 @@
+package robotstxt
+
+/*
+#include "robots_c.h"
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+)
+
+// ABIVersion is the native C ABI version this binding is written against.
+const ABIVersion = 1
+
+// ErrIncompatibleABI is wrapped by EnsureCompatible's error when the header
+// or the linked native library has a different ABI version.
+var ErrIncompatibleABI = errors.New("robotstxt: incompatible native library ABI")
+
+// abiErr is checked once, when the package is initialized.
+var abiErr = checkABI(int(C.ROBOTS_ABI_VERSION), int(C.robots_abi_version()))
+
+// EnsureCompatible reports whether the native library matches this binding.
+// Call it at startup when linking against a shared librobots: a mismatched
+// library returns a *BackendError naming both versions here, where it would
+// otherwise corrupt memory at the first call that uses a changed struct.
+func EnsureCompatible() error {
+	if abiErr != nil {
+		return &BackendError{Backend: "cgo", Err: abiErr}
+	}
+	return nil
+}
+
+// checkABI compares the ABI versions of the compiled-in header and of the
+// linked library with ABIVersion.
+func checkABI(header, library int) error {
+	switch {
+	case header != ABIVersion:
+		return fmt.Errorf("%w: built against robots_c.h ABI %d, binding expects %d", ErrIncompatibleABI, header, ABIVersion)
+	case library != ABIVersion:
+		return fmt.Errorf("%w: linked librobots has ABI %d, binding expects %d", ErrIncompatibleABI, library, ABIVersion)
+	}
+	return nil
+}
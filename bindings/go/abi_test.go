@@ -0,0 +1,24 @@
+package robotstxt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEnsureCompatible(t *testing.T) {
+	if err := EnsureCompatible(); err != nil {
+		t.Errorf("EnsureCompatible = %v", err)
+	}
+	if err := checkABI(ABIVersion, ABIVersion); err != nil {
+		t.Errorf("checkABI(matching) = %v", err)
+	}
+	err := checkABI(ABIVersion, ABIVersion+1)
+	if !errors.Is(err, ErrIncompatibleABI) || !strings.Contains(err.Error(), "librobots has ABI 2") {
+		t.Errorf("checkABI(library mismatch) = %v", err)
+	}
+	err = checkABI(ABIVersion+1, ABIVersion)
+	if !errors.Is(err, ErrIncompatibleABI) || !strings.Contains(err.Error(), "robots_c.h ABI 2") {
+		t.Errorf("checkABI(header mismatch) = %v", err)
+	}
+}
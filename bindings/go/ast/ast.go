@@ -0,0 +1,216 @@
+// Package ast provides a lossless syntax tree for robots.txt files. Every
+// byte of the source, including comments, whitespace and line endings, is
+// kept in a token, so a File serializes back to its source byte for byte
+// and edits only touch the lines they change.
+//
+// Example usage:
+//
+//	f := ast.Parse(src)
+//	for _, l := range f.Lines {
+//		if l.Type == robotstxt.DirectiveDisallow && l.Value == "/tmp/" {
+//			l.Value = "/temp/"
+//		}
+//	}
+//	os.WriteFile(path, f.Bytes(), 0o644)
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	robotstxt "github.com/nzrsky/robotstxt/bindings/go"
+)
+
+const bom = "\ufeff"
+
+// File is a parsed robots.txt.
+type File struct {
+	// BOM holds a leading UTF-8 byte order mark, if any.
+	BOM   string
+	Lines []*Line
+}
+
+// Line is one physical line. Its tokens, concatenated in field order
+// (Indent, Key, Sep, Value, Space, Comment, EOL), reproduce the line.
+type Line struct {
+	// Offset is the byte offset of the line in the source, or -1 for lines
+	// added after parsing. Number is the 1-based line number, or 0.
+	Offset int
+	Number int
+
+	// Directive reports whether the parser recognized the line as a
+	// directive; Type is its kind as parsed. Lines the parser ignores keep
+	// their text in Value.
+	Directive bool
+	Type      robotstxt.DirectiveType
+
+	Indent  string // leading whitespace
+	Key     string // directive name as written
+	Sep     string // separator with surrounding whitespace, e.g. ": "
+	Value   string
+	Space   string // whitespace between value and comment or end of line
+	Comment string // "#" comment, including the hash
+	EOL     string // "\n", "\r\n", "\r", or "" on the last line
+}
+
+// Parse builds the syntax tree of src. Directive recognition, including
+// accepted typos, is the same as the matcher's.
+func Parse(src []byte) *File {
+	text := string(src)
+	types := make(map[int]robotstxt.DirectiveType)
+	for _, d := range robotstxt.Parse(text).Directives {
+		types[d.Line] = d.Type
+	}
+
+	f := &File{}
+	offset := 0
+	if strings.HasPrefix(text, bom) {
+		f.BOM = bom
+		text = text[len(bom):]
+		offset = len(bom)
+	}
+	for n := 1; ; n++ {
+		end := strings.IndexAny(text, "\r\n")
+		line, eol := text, ""
+		if end >= 0 {
+			line, eol = text[:end], text[end:end+1]
+			if eol == "\r" && strings.HasPrefix(text[end+1:], "\n") {
+				eol = "\r\n"
+			}
+		}
+		if line == "" && eol == "" {
+			break
+		}
+		l := tokenize(line)
+		l.Offset, l.Number, l.EOL = offset, n, eol
+		l.Type, l.Directive = types[n]
+		f.Lines = append(f.Lines, l)
+		if end < 0 {
+			break
+		}
+		offset += len(line) + len(eol)
+		text = text[len(line)+len(eol):]
+	}
+	return f
+}
+
+// tokenize splits a line without its line ending into tokens, following the
+// parser's key/value rules: the first colon separates key and value, and
+// whitespace may stand in for a missing colon.
+func tokenize(line string) *Line {
+	l := &Line{}
+	rest := strings.TrimLeft(line, " \t")
+	l.Indent = line[:len(line)-len(rest)]
+	if i := strings.IndexByte(rest, '#'); i >= 0 {
+		l.Comment = rest[i:]
+		rest = rest[:i]
+	}
+	body := strings.TrimRight(rest, " \t")
+	l.Space = rest[len(body):]
+
+	sep := strings.IndexByte(body, ':')
+	if sep < 0 {
+		sep = strings.IndexAny(body, " \t")
+	}
+	if sep < 0 {
+		l.Value = body
+		return l
+	}
+	l.Key = strings.TrimRight(body[:sep], " \t")
+	after := body[sep:]
+	if after[0] == ':' {
+		after = after[1:]
+	}
+	l.Value = strings.TrimLeft(after, " \t")
+	l.Sep = body[len(l.Key) : len(body)-len(l.Value)]
+	return l
+}
+
+// NewDirective returns a line for "key: value", to be added with Insert.
+func NewDirective(key, value string) *Line {
+	l := &Line{Offset: -1, Key: key, Sep: ": ", Value: value}
+	for _, d := range robotstxt.Parse(l.String()).Directives {
+		l.Directive, l.Type = true, d.Type
+	}
+	return l
+}
+
+// String returns the line's source text, including its line ending.
+func (l *Line) String() string {
+	return l.Indent + l.Key + l.Sep + l.Value + l.Space + l.Comment + l.EOL
+}
+
+// ValueOffset returns the byte offset of Value in the source, or -1 for
+// added lines. It reflects the tokens as parsed.
+func (l *Line) ValueOffset() int {
+	if l.Offset < 0 {
+		return -1
+	}
+	return l.Offset + len(l.Indent) + len(l.Key) + len(l.Sep)
+}
+
+// Disable turns a directive into a comment ("# Disallow: /x") and Enable
+// reverses it, for toggle operations. Both leave other lines untouched.
+func (l *Line) Disable() {
+	if !l.Directive {
+		return
+	}
+	l.Comment = "# " + l.Key + l.Sep + l.Value + l.Space + l.Comment
+	l.Key, l.Sep, l.Value, l.Space, l.Directive = "", "", "", "", false
+}
+
+// Enable re-parses a comment written by Disable as a directive. It reports
+// whether the comment held a recognized directive.
+func (l *Line) Enable() bool {
+	if l.Directive || l.Key != "" || l.Value != "" || !strings.HasPrefix(l.Comment, "#") {
+		return false
+	}
+	t := tokenize(strings.TrimLeft(l.Comment[1:], " \t"))
+	probe := NewDirective(t.Key, t.Value)
+	if !probe.Directive {
+		return false
+	}
+	l.Key, l.Sep, l.Value, l.Space, l.Comment = t.Key, t.Sep, t.Value, t.Space, t.Comment
+	l.Directive, l.Type = true, probe.Type
+	return true
+}
+
+// Insert inserts lines before index i (len(f.Lines) appends). Lines without
+// a line ending get the file's, and a previously last line gains one.
+func (f *File) Insert(i int, lines ...*Line) {
+	eol := f.eol()
+	if i > 0 && i == len(f.Lines) && f.Lines[i-1].EOL == "" {
+		f.Lines[i-1].EOL = eol
+	}
+	for _, l := range lines {
+		if l.EOL == "" {
+			l.EOL = eol
+		}
+	}
+	f.Lines = append(f.Lines[:i], append(lines, f.Lines[i:]...)...)
+}
+
+// Delete removes the line at index i.
+func (f *File) Delete(i int) {
+	f.Lines = append(f.Lines[:i], f.Lines[i+1:]...)
+}
+
+// eol returns the file's first line ending, or "\n".
+func (f *File) eol() string {
+	for _, l := range f.Lines {
+		if l.EOL != "" {
+			return l.EOL
+		}
+	}
+	return "\n"
+}
+
+// Bytes serializes the file. An unmodified File yields its source exactly.
+func (f *File) Bytes() []byte {
+	var b bytes.Buffer
+	b.WriteString(f.BOM)
+	for _, l := range f.Lines {
+		b.WriteString(l.String())
+	}
+	return b.Bytes()
+}
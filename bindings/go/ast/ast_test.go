@@ -0,0 +1,96 @@
+package ast
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	robotstxt "github.com/nzrsky/robotstxt/bindings/go"
+)
+
+func TestRoundTrip(t *testing.T) {
+	for _, src := range []string{
+		"",
+		"\ufeffUser-agent: *\nDisallow: /\n",
+		"User-agent:FooBot  # the bot\r\n\t disalow :  /private/ \r\n\r\n# comment only\rSitemap https://example.com/s.xml",
+		"garbage line here\n\n\n   \nAllow: /a#b\n",
+	} {
+		if got := string(Parse([]byte(src)).Bytes()); got != src {
+			t.Errorf("Round trip of %q gave %q", src, got)
+		}
+	}
+}
+
+// source is random robots.txt-like text: directive fragments, separators,
+// comments, whitespace and every kind of line ending.
+type source string
+
+func (source) Generate(r *rand.Rand, size int) reflect.Value {
+	pieces := []string{"User-agent", "Disallow", "disalow", "Allow", "Sitemap", "*", "/a", "foo",
+		":", " : ", " ", "\t", "#", "# c", "\n", "\r\n", "\r", "\ufeff", "https://x/"}
+	var b []byte
+	for n := r.Intn(4 * (size + 1)); n > 0; n-- {
+		b = append(b, pieces[r.Intn(len(pieces))]...)
+	}
+	return reflect.ValueOf(source(b))
+}
+
+func TestRoundTripProperty(t *testing.T) {
+	lossless := func(src source) bool {
+		f := Parse([]byte(src))
+		again := Parse(f.Bytes())
+		return string(f.Bytes()) == string(src) && reflect.DeepEqual(again, f)
+	}
+	if err := quick.Check(lossless, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTokens(t *testing.T) {
+	f := Parse([]byte("User-agent: *\n  Disalow :  /private/  # no\nDisallow /x\nfoo bar baz\n"))
+	if len(f.Lines) != 4 {
+		t.Fatalf("Expected 4 lines, got %d", len(f.Lines))
+	}
+	l := f.Lines[1]
+	if !l.Directive || l.Type != robotstxt.DirectiveDisallow || l.Indent != "  " || l.Key != "Disalow" ||
+		l.Sep != " :  " || l.Value != "/private/" || l.Space != "  " || l.Comment != "# no" || l.EOL != "\n" {
+		t.Errorf("Unexpected tokens %+v", l)
+	}
+	if l.Offset != 14 || l.Number != 2 || l.ValueOffset() != 14+2+7+4 {
+		t.Errorf("Unexpected positions: offset %d, number %d, value offset %d", l.Offset, l.Number, l.ValueOffset())
+	}
+	if s := f.Lines[2]; !s.Directive || s.Type != robotstxt.DirectiveDisallow || s.Sep != " " {
+		t.Errorf("Expected missing-colon disallow, got %+v", s)
+	}
+	if g := f.Lines[3]; g.Directive {
+		t.Errorf("Expected garbage line not to be a directive, got %+v", g)
+	}
+}
+
+func TestEdits(t *testing.T) {
+	src := "User-agent: *\r\nDisallow: /tmp/   # old\r\nAllow: /"
+	f := Parse([]byte(src))
+	f.Lines[1].Value = "/temp/"
+	f.Insert(len(f.Lines), NewDirective("Crawl-delay", "5"))
+	want := "User-agent: *\r\nDisallow: /temp/   # old\r\nAllow: /\r\nCrawl-delay: 5\r\n"
+	if got := string(f.Bytes()); got != want {
+		t.Errorf("Got %q, want %q", got, want)
+	}
+	if f.Lines[3].Type != robotstxt.DirectiveCrawlDelay {
+		t.Errorf("Expected crawl-delay type, got %v", f.Lines[3].Type)
+	}
+
+	f.Lines[1].Disable()
+	if got := f.Lines[1].String(); got != "# Disallow: /temp/   # old\r\n" {
+		t.Errorf("Disable gave %q", got)
+	}
+	if !f.Lines[1].Enable() || f.Lines[1].String() != "Disallow: /temp/   # old\r\n" || f.Lines[1].Type != robotstxt.DirectiveDisallow {
+		t.Errorf("Enable gave %q", f.Lines[1].String())
+	}
+
+	f.Delete(0)
+	if got := string(f.Bytes()); got != "Disallow: /temp/   # old\r\nAllow: /\r\nCrawl-delay: 5\r\n" {
+		t.Errorf("Delete gave %q", got)
+	}
+}
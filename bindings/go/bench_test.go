@@ -0,0 +1,154 @@
+package robotstxt
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nzrsky/robotstxt/bindings/go/gen"
+)
+
+// The corpus benchmarks mirror tests/robots_benchmark.cc. They use the
+// real-world files from benchmark-utils/download-data.sh when present and a
+// generated corpus otherwise, so results are comparable with benchstat
+// across commits on the same machine.
+
+const benchCorpusPath = "../../robots_files/robots_all.bin"
+
+var benchCorpus struct {
+	once  sync.Once
+	files []string
+	bytes int64
+}
+
+func loadBenchCorpus() []string {
+	benchCorpus.once.Do(func() {
+		files, err := readRobotsBin(benchCorpusPath)
+		if err != nil || len(files) == 0 {
+			files = gen.Corpus(gen.Options{Seed: 1, Groups: 3, Rules: 40, Wildcards: 0.2, Junk: 0.05, MinSize: 256, MaxSize: 64 << 10}, 500)
+		}
+		benchCorpus.files = files
+		for _, f := range files {
+			benchCorpus.bytes += int64(len(f))
+		}
+	})
+	return benchCorpus.files
+}
+
+// readRobotsBin reads the benchmark data format: repeated
+// [uint32_le length][content bytes] records.
+func readRobotsBin(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var files []string
+	for {
+		var n uint32
+		if err := binary.Read(f, binary.LittleEndian, &n); err == io.EOF {
+			return files, nil
+		} else if err != nil {
+			return nil, err
+		}
+		data := make([]byte, n)
+		if _, err := io.ReadFull(f, data); err != nil {
+			return nil, err
+		}
+		files = append(files, string(data))
+	}
+}
+
+func BenchmarkParseOnly(b *testing.B) {
+	files := loadBenchCorpus()
+	b.SetBytes(benchCorpus.bytes)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, f := range files {
+			Parse(f)
+		}
+	}
+}
+
+func BenchmarkParseAndMatch(b *testing.B) {
+	files := loadBenchCorpus()
+	m := NewMatcher()
+	defer m.Free()
+	b.SetBytes(benchCorpus.bytes)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, f := range files {
+			m.IsAllowed(f, "Googlebot", "https://example.com/")
+		}
+	}
+}
+
+func BenchmarkParseAndMatchSingle(b *testing.B) {
+	files := loadBenchCorpus()
+	f := files[len(files)/2]
+	m := NewMatcher()
+	defer m.Free()
+	b.SetBytes(int64(len(f)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.IsAllowed(f, "Googlebot", "https://example.com/test/path")
+	}
+}
+
+func BenchmarkMatchMultipleUserAgents(b *testing.B) {
+	files := loadBenchCorpus()
+	f := files[len(files)/2]
+	agents := []string{"Googlebot", "Googlebot-Image", "Googlebot-News"}
+	m := NewMatcher()
+	defer m.Free()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.IsAllowedMulti(f, agents, "https://example.com/some/path/to/check")
+	}
+}
+
+// BenchmarkMatchOnly checks many URLs against one small file, so the cost
+// is dominated by matching rather than parsing.
+func BenchmarkMatchOnly(b *testing.B) {
+	robotsTxt := "User-agent: *\nDisallow: /private/\nAllow: /private/public/\nDisallow: /*.pdf$\n"
+	urls := []string{
+		"https://example.com/",
+		"https://example.com/private/x",
+		"https://example.com/private/public/y",
+		"https://example.com/docs/manual.pdf",
+	}
+	m := NewMatcher()
+	defer m.Free()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.IsAllowed(robotsTxt, "Googlebot", urls[i%len(urls)])
+	}
+}
+
+// BenchmarkWorstCaseWildcards matches a long path against many rules with
+// several wildcards each, the matcher's most expensive input.
+func BenchmarkWorstCaseWildcards(b *testing.B) {
+	var sb strings.Builder
+	sb.WriteString("User-agent: *\n")
+	for i := 0; i < 200; i++ {
+		sb.WriteString("Disallow: /*a*b*c*d*e*f*g*h*z\n")
+	}
+	robotsTxt := sb.String()
+	url := "https://example.com/" + strings.Repeat("abcdefgh", 250)
+	m := NewMatcher()
+	defer m.Free()
+	b.SetBytes(int64(len(robotsTxt)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.IsAllowed(robotsTxt, "Googlebot", url)
+	}
+}
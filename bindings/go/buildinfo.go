@@ -0,0 +1,38 @@
+package robotstxt
+
+/*
+#include "robots_c.h"
+*/
+import "C"
+
+// LibraryBuild describes how the native library was compiled.
+type LibraryBuild struct {
+	Version string `json:"version"`
+	// Commit is the source commit the library was built from, or "" if the
+	// build did not record one.
+	Commit string `json:"commit,omitempty"`
+	// Compiler is the compiler name and version, e.g. "gcc 13.2.0".
+	Compiler string `json:"compiler"`
+	// CPlusPlus is the C++ standard the library was built with, as the
+	// value of __cplusplus (201703 for C++17).
+	CPlusPlus int64 `json:"cplusplus"`
+	// ContentSignal reports Content-Signal directive support.
+	ContentSignal bool `json:"content_signal"`
+	// Ada reports URL parsing with the ada library.
+	Ada bool `json:"ada"`
+}
+
+// BuildInfo reports how the native library was compiled, for bug reports
+// and for keying caches of results that depend on the parser build.
+func BuildInfo() LibraryBuild {
+	var info C.robots_build_info_t
+	C.robots_build_info(&info)
+	return LibraryBuild{
+		Version:       C.GoString(info.version),
+		Commit:        C.GoString(info.commit),
+		Compiler:      C.GoString(info.compiler),
+		CPlusPlus:     int64(info.cplusplus),
+		ContentSignal: bool(info.content_signal),
+		Ada:           bool(info.ada),
+	}
+}
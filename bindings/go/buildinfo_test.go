@@ -0,0 +1,27 @@
+package robotstxt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildInfo(t *testing.T) {
+	b := BuildInfo()
+	if b.Version != Version() {
+		t.Errorf("Version = %q, want %q", b.Version, Version())
+	}
+	if b.Compiler == "" || b.Compiler == "unknown" {
+		t.Errorf("Compiler = %q", b.Compiler)
+	}
+	if b.CPlusPlus < 201703 {
+		t.Errorf("CPlusPlus = %d, want at least C++17", b.CPlusPlus)
+	}
+	// The cgo build compiles the single header with Content-Signal support
+	// and without ada.
+	if !b.ContentSignal || b.Ada {
+		t.Errorf("Unexpected features %+v", b)
+	}
+	if strings.ContainsAny(b.Commit, " \n") {
+		t.Errorf("Commit = %q", b.Commit)
+	}
+}
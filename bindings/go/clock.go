@@ -0,0 +1,30 @@
+package robotstxt
+
+import "time"
+
+// Clock supplies the current time and timers to time-dependent code
+// (Fetcher, PolitenessProfile, MemoryPolitenessStore, monitor.Monitor), so
+// tests can fast-forward time. A nil Clock means SystemClock; see
+// robotstest.Clock for a manual one.
+type Clock interface {
+	Now() time.Time
+	// After waits for d to elapse and then sends the current time on the
+	// returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock of the time package.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// clockOr returns c, or SystemClock if c is nil.
+func clockOr(c Clock) Clock {
+	if c == nil {
+		return SystemClock
+	}
+	return c
+}
@@ -0,0 +1,55 @@
+package robotstxt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// stepClock is a Clock whose timers fire at once, advancing the time.
+type stepClock struct {
+	now time.Time
+}
+
+func (c *stepClock) Now() time.Time { return c.now }
+
+func (c *stepClock) After(d time.Duration) <-chan time.Time {
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func TestClockInjection(t *testing.T) {
+	start := time.Date(2024, 5, 1, 2, 0, 0, 0, time.UTC)
+	clock := &stepClock{now: start}
+	ctx := context.Background()
+
+	rr := &RequestRate{Requests: 1, Seconds: 10, Window: &TimeWindow{Start: 6 * time.Hour, End: 14 * time.Hour}}
+	p := PolitenessProfile{Origin: "https://example.com", RequestRate: rr, MinDelay: time.Hour, Clock: clock}
+	if got, want := p.NextAllowedFetch(time.Time{}), start.Add(4*time.Hour); !got.Equal(want) {
+		t.Errorf("NextAllowedFetch(never) = %v, want %v", got, want)
+	}
+
+	s := &MemoryPolitenessStore{Clock: clock}
+	for i := 0; i < 3; i++ {
+		if err := p.Wait(ctx, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := clock.Now(), start.Add(2*time.Hour); !got.Equal(want) {
+		t.Errorf("After three Waits the clock is at %v, want %v", got, want)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	res, err := (&Fetcher{Clock: clock}).Fetch(ctx, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.FetchedAt.Equal(clock.Now()) {
+		t.Errorf("FetchedAt = %v, want %v", res.FetchedAt, clock.Now())
+	}
+}
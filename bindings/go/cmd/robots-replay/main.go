@@ -0,0 +1,51 @@
+// Command robots-replay re-evaluates an audit log of past robots.txt
+// decisions under the current parser and prints every changed verdict as a
+// JSON line. It exits with status 1 if any verdict changed.
+//
+// Usage:
+//
+//	robots-replay -bodies DIR [audit.jsonl]
+//
+// DIR holds the archived robots.txt bodies, each named by its hash. The
+// audit log is read from stdin if no file is given.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/nzrsky/robotstxt/bindings/go/replay"
+)
+
+func main() {
+	bodies := flag.String("bodies", ".", "directory of archived robots.txt bodies named by hash")
+	flag.Parse()
+
+	in := os.Stdin
+	if flag.NArg() > 0 {
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	rep, err := replay.Run(in, os.DirFS(*bodies))
+	if err != nil {
+		log.Fatal(err)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	for _, c := range rep.Changes {
+		if err := enc.Encode(c); err != nil {
+			log.Fatal(err)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%d records, %d changed, %d missing bodies\n", rep.Total, len(rep.Changes), rep.Missing)
+	if len(rep.Changes) > 0 {
+		os.Exit(1)
+	}
+}
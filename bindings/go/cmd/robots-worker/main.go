@@ -0,0 +1,16 @@
+// Command robots-worker is a worker process for the worker package. It reads
+// JSON check requests from stdin and writes results to stdout.
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/nzrsky/robotstxt/bindings/go/worker"
+)
+
+func main() {
+	if err := worker.Serve(os.Stdin, os.Stdout); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,181 @@
+package robotstxt
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// TriState is a yes/no preference that may be left unset. The zero value is
+// Unset.
+type TriState int8
+
+// TriState values.
+const (
+	Unset TriState = iota
+	Yes
+	No
+)
+
+// TriStateOf returns Yes for true and No for false.
+func TriStateOf(b bool) TriState {
+	if b {
+		return Yes
+	}
+	return No
+}
+
+// TriStatePtr converts the older *bool form: nil is Unset.
+func TriStatePtr(b *bool) TriState {
+	if b == nil {
+		return Unset
+	}
+	return TriStateOf(*b)
+}
+
+// IsSet reports whether t is Yes or No.
+func (t TriState) IsSet() bool { return t == Yes || t == No }
+
+// Or returns t as a bool, or def if t is unset.
+func (t TriState) Or(def bool) bool {
+	if !t.IsSet() {
+		return def
+	}
+	return t == Yes
+}
+
+// Ptr returns t in the older *bool form: nil if unset.
+func (t TriState) Ptr() *bool {
+	if !t.IsSet() {
+		return nil
+	}
+	b := t == Yes
+	return &b
+}
+
+// String returns "yes", "no" or "unset".
+func (t TriState) String() string {
+	switch t {
+	case Yes:
+		return "yes"
+	case No:
+		return "no"
+	}
+	return "unset"
+}
+
+// MarshalJSON encodes Yes and No as "yes" and "no", and Unset as null.
+func (t TriState) MarshalJSON() ([]byte, error) {
+	if !t.IsSet() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON accepts "yes", "no", true, false and null.
+func (t *TriState) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v {
+	case nil:
+		*t = Unset
+	case "yes", true:
+		*t = Yes
+	case "no", false:
+		*t = No
+	default:
+		return fmt.Errorf("robotstxt: invalid tri-state %s", data)
+	}
+	return nil
+}
+
+// AITrainPtr returns AITrain in the *bool form the field had before
+// TriState: nil means not set.
+func (cs ContentSignal) AITrainPtr() *bool { return cs.AITrain.Ptr() }
+
+// AIInputPtr returns AIInput as a *bool; see AITrainPtr.
+func (cs ContentSignal) AIInputPtr() *bool { return cs.AIInput.Ptr() }
+
+// SearchPtr returns Search as a *bool; see AITrainPtr.
+func (cs ContentSignal) SearchPtr() *bool { return cs.Search.Ptr() }
+
+// ParseContentSignal strictly parses a Content-Signal value such as
+// "search=yes, ai-train=no", for validating values before publishing them.
+// Unlike the matcher, which skips what it does not understand, it rejects
+// unknown keys, repeated keys and values other than "yes" and "no".
+func ParseContentSignal(value string) (ContentSignal, error) {
+	var cs ContentSignal
+	if strings.TrimSpace(value) == "" {
+		return cs, fmt.Errorf("robotstxt: empty content signal")
+	}
+	for _, part := range strings.Split(value, ",") {
+		eq := strings.IndexByte(part, '=')
+		if eq < 0 {
+			return cs, fmt.Errorf("robotstxt: content signal %q is not key=value", strings.TrimSpace(part))
+		}
+		key := strings.ToLower(strings.TrimSpace(part[:eq]))
+		var field *TriState
+		switch key {
+		case "search":
+			field = &cs.Search
+		case "ai-input":
+			field = &cs.AIInput
+		case "ai-train":
+			field = &cs.AITrain
+		default:
+			return cs, fmt.Errorf("robotstxt: unknown content signal %q", key)
+		}
+		if *field != Unset {
+			return cs, fmt.Errorf("robotstxt: content signal %q repeated", key)
+		}
+		switch val := strings.ToLower(strings.TrimSpace(part[eq+1:])); val {
+		case "yes":
+			*field = Yes
+		case "no":
+			*field = No
+		default:
+			return cs, fmt.Errorf("robotstxt: content signal %s=%q is not yes or no", key, val)
+		}
+	}
+	return cs, nil
+}
+
+// MergeContentSignals combines the signals for one document from several
+// sources, such as robots.txt, llms.txt and HTTP headers, into one decision.
+// Per field, the most restrictive value wins: No over Yes over Unset. The
+// order of signals does not matter. Use OverlayContentSignals to rank the
+// sources instead.
+func MergeContentSignals(signals ...ContentSignal) ContentSignal {
+	var out ContentSignal
+	merge := func(dst *TriState, v TriState) {
+		if v == No || v == Yes && *dst == Unset {
+			*dst = v
+		}
+	}
+	for _, cs := range signals {
+		merge(&out.AITrain, cs.AITrain)
+		merge(&out.AIInput, cs.AIInput)
+		merge(&out.Search, cs.Search)
+	}
+	return out
+}
+
+// OverlayContentSignals combines signals given in order of precedence: per
+// field, the first set value wins, so a specific source can relax a general
+// one.
+func OverlayContentSignals(signals ...ContentSignal) ContentSignal {
+	var out ContentSignal
+	overlay := func(dst *TriState, v TriState) {
+		if *dst == Unset {
+			*dst = v
+		}
+	}
+	for _, cs := range signals {
+		overlay(&out.AITrain, cs.AITrain)
+		overlay(&out.AIInput, cs.AIInput)
+		overlay(&out.Search, cs.Search)
+	}
+	return out
+}
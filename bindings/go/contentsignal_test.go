@@ -0,0 +1,71 @@
+package robotstxt
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseContentSignal(t *testing.T) {
+	cs, err := ParseContentSignal(" search=yes,AI-Train = No ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cs.Search != Yes || cs.AITrain != No || cs.AIInput != Unset {
+		t.Errorf("ParseContentSignal = %+v", cs)
+	}
+	for _, bad := range []string{"", "search", "ai-tran=no", "search=true", "search=yes, search=no", "search=yes,"} {
+		if _, err := ParseContentSignal(bad); err == nil {
+			t.Errorf("ParseContentSignal(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestTriState(t *testing.T) {
+	if TriStateOf(true) != Yes || TriStateOf(false) != No || TriStatePtr(nil) != Unset {
+		t.Error("TriState constructors")
+	}
+	if p := No.Ptr(); p == nil || *p || Unset.Ptr() != nil || TriStatePtr(Yes.Ptr()) != Yes {
+		t.Error("TriState.Ptr round trip")
+	}
+	if !Unset.Or(true) || Unset.Or(false) || No.Or(true) || !Yes.Or(false) {
+		t.Error("TriState.Or")
+	}
+
+	cs := ContentSignal{AITrain: No, Search: Yes}
+	data, err := json.Marshal(cs)
+	if err != nil || string(data) != `{"ai_train":"no","search":"yes"}` {
+		t.Errorf("Marshal = %s, %v", data, err)
+	}
+	var back ContentSignal
+	if err := json.Unmarshal([]byte(`{"ai_train":false,"ai_input":null,"search":"yes"}`), &back); err != nil || back != cs {
+		t.Errorf("Unmarshal = %+v, %v, want %+v", back, err, cs)
+	}
+	if err := json.Unmarshal([]byte(`{"search":"maybe"}`), &back); err == nil {
+		t.Error("Unmarshal of invalid tri-state succeeded")
+	}
+	if p := cs.AITrainPtr(); p == nil || *p || cs.AIInputPtr() != nil || !*cs.SearchPtr() {
+		t.Error("compatibility accessors")
+	}
+}
+
+func TestMergeContentSignals(t *testing.T) {
+	robots := ContentSignal{AITrain: No, Search: Yes}
+	llms := ContentSignal{AITrain: Yes, AIInput: Yes}
+	header := ContentSignal{Search: No}
+
+	want := ContentSignal{AITrain: No, AIInput: Yes, Search: No}
+	if got := MergeContentSignals(robots, llms, header); got != want {
+		t.Errorf("MergeContentSignals = %+v, want %+v", got, want)
+	}
+	if got := MergeContentSignals(header, llms, robots); got != want {
+		t.Errorf("MergeContentSignals depends on order: %+v", got)
+	}
+	if got := MergeContentSignals(); got != (ContentSignal{}) {
+		t.Errorf("MergeContentSignals() = %+v, want unset", got)
+	}
+
+	want = ContentSignal{AITrain: Yes, AIInput: Yes, Search: Yes}
+	if got := OverlayContentSignals(llms, robots, header); got != want {
+		t.Errorf("OverlayContentSignals = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,138 @@
+package robotstxt
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CrawlDelayOptions controls how ParseCrawlDelay interprets crawl-delay
+// values. The zero value accepts only plain, non-negative decimal seconds
+// such as "10" or "0.5" and applies no clamping.
+type CrawlDelayOptions struct {
+	// Units accepts a unit suffix: s, sec, m, min, h or hr (and their long
+	// forms), as in "1m" or "30 sec". Values without a unit are seconds.
+	Units bool
+	// CommaDecimal accepts a comma as the decimal separator, as in "0,5".
+	CommaDecimal bool
+	// Min and Max clamp the parsed delay. Zero means no bound.
+	Min time.Duration
+	Max time.Duration
+}
+
+// CrawlDelayError reports a crawl-delay value that could not be parsed.
+type CrawlDelayError struct {
+	// Line is the robots.txt line of the directive, or 0 for a bare value.
+	Line  int
+	Value string
+}
+
+func (e *CrawlDelayError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("robotstxt: invalid crawl-delay %q", e.Value)
+	}
+	return fmt.Sprintf("robotstxt: invalid crawl-delay %q on line %d", e.Value, e.Line)
+}
+
+var crawlDelayUnits = map[string]time.Duration{
+	"s": time.Second, "sec": time.Second, "secs": time.Second,
+	"second": time.Second, "seconds": time.Second,
+	"m": time.Minute, "min": time.Minute, "mins": time.Minute,
+	"minute": time.Minute, "minutes": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hrs": time.Hour,
+	"hour": time.Hour, "hours": time.Hour,
+}
+
+// ParseCrawlDelay parses a crawl-delay value according to opts. Unlike the
+// matcher, which treats garbage as 0, it returns a *CrawlDelayError for
+// values it cannot interpret.
+func ParseCrawlDelay(value string, opts CrawlDelayOptions) (time.Duration, error) {
+	invalid := &CrawlDelayError{Value: value}
+	num := strings.TrimSpace(value)
+	unit := time.Second
+	if opts.Units {
+		end := strings.IndexFunc(num, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.' && r != ','
+		})
+		if end >= 0 {
+			u, ok := crawlDelayUnits[strings.ToLower(strings.TrimSpace(num[end:]))]
+			if !ok {
+				return 0, invalid
+			}
+			num, unit = num[:end], u
+		}
+	}
+	if opts.CommaDecimal && !strings.Contains(num, ".") && strings.Count(num, ",") == 1 {
+		num = strings.Replace(num, ",", ".", 1)
+	}
+	if num == "" || strings.Trim(num, "0123456789.") != "" {
+		return 0, invalid
+	}
+	secs, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, invalid
+	}
+	d := secs * float64(unit)
+	if opts.Max > 0 && d > float64(opts.Max) {
+		d = float64(opts.Max)
+	}
+	if opts.Min > 0 && d < float64(opts.Min) {
+		d = float64(opts.Min)
+	}
+	if d >= math.MaxInt64 {
+		return 0, invalid
+	}
+	return time.Duration(d), nil
+}
+
+// CrawlDelayAt parses the crawl-delay directive on the given line of
+// robotsTxt, as reported by CheckResult.CrawlDelayLine.
+func CrawlDelayAt(robotsTxt string, line int, opts CrawlDelayOptions) (time.Duration, error) {
+	value := directiveValue(lineAt(robotsTxt, line))
+	d, err := ParseCrawlDelay(value, opts)
+	if err != nil {
+		return 0, &CrawlDelayError{Line: line, Value: value}
+	}
+	return d, nil
+}
+
+// CrawlDelayWith re-parses the crawl-delay reported by the most recent check
+// of robotsTxt using opts. It returns nil and no error if there is none.
+func (m *Matcher) CrawlDelayWith(robotsTxt string, opts CrawlDelayOptions) (*time.Duration, error) {
+	line := m.CrawlDelayLine()
+	if line == 0 {
+		return nil, nil
+	}
+	d, err := CrawlDelayAt(robotsTxt, line, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// lineAt returns the 1-based line n of body, or "" if there is none.
+func lineAt(body string, n int) string {
+	lines := splitLines(body)
+	if n < 1 || n > len(lines) {
+		return ""
+	}
+	return lines[n-1]
+}
+
+// directiveValue returns the value part of a robots.txt line, accepting
+// whitespace in place of a missing colon like the parser does.
+func directiveValue(line string) string {
+	if i := strings.IndexByte(line, '#'); i >= 0 {
+		line = line[:i]
+	}
+	line = strings.TrimSpace(line)
+	if i := strings.IndexByte(line, ':'); i >= 0 {
+		return strings.TrimSpace(line[i+1:])
+	}
+	if i := strings.IndexAny(line, " \t"); i >= 0 {
+		return strings.TrimSpace(line[i:])
+	}
+	return ""
+}
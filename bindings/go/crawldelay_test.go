@@ -0,0 +1,95 @@
+package robotstxt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseCrawlDelay(t *testing.T) {
+	tolerant := CrawlDelayOptions{Units: true, CommaDecimal: true}
+	tests := []struct {
+		value string
+		opts  CrawlDelayOptions
+		want  time.Duration
+		ok    bool
+	}{
+		{"10", CrawlDelayOptions{}, 10 * time.Second, true},
+		{"0.5", CrawlDelayOptions{}, 500 * time.Millisecond, true},
+		{"1m", CrawlDelayOptions{}, 0, false},
+		{"0,5", CrawlDelayOptions{}, 0, false},
+		{"1m", tolerant, time.Minute, true},
+		{"30 sec", tolerant, 30 * time.Second, true},
+		{"2H", tolerant, 2 * time.Hour, true},
+		{"0,5", tolerant, 500 * time.Millisecond, true},
+		{"1,000,000", tolerant, 0, false},
+		{"fast", tolerant, 0, false},
+		{"-5", tolerant, 0, false},
+		{"NaN", CrawlDelayOptions{}, 0, false},
+		{"1e999", CrawlDelayOptions{}, 0, false},
+		{"86400", CrawlDelayOptions{Max: time.Minute}, time.Minute, true},
+		{"0", CrawlDelayOptions{Min: time.Second}, time.Second, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCrawlDelay(tt.value, tt.opts)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseCrawlDelay(%q, %+v) = %v, %v; want %v, ok=%v", tt.value, tt.opts, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestMatcherCrawlDelayWith(t *testing.T) {
+	m := NewMatcher()
+	defer m.Free()
+
+	robotsTxt := "User-agent: *\r\nCrawl-delay: 10\r\n\r\nUser-agent: FooBot\r\nCrawl-delay: 1m # slow\r\n\r\nUser-agent: BarBot\r\nCrawl-delay fast\r\n"
+
+	m.IsAllowed(robotsTxt, "FooBot", "https://example.com/")
+	if line := m.CrawlDelayLine(); line != 5 {
+		t.Errorf("Expected crawl-delay line 5, got %d", line)
+	}
+	d, err := m.CrawlDelayWith(robotsTxt, CrawlDelayOptions{Units: true})
+	if err != nil || d == nil || *d != time.Minute {
+		t.Errorf("Expected 1m, got %v, %v", d, err)
+	}
+
+	m.IsAllowed(robotsTxt, "BarBot", "https://example.com/")
+	_, err = m.CrawlDelayWith(robotsTxt, CrawlDelayOptions{Units: true})
+	var cdErr *CrawlDelayError
+	if !errors.As(err, &cdErr) || cdErr.Line != 8 || cdErr.Value != "fast" {
+		t.Errorf("Expected CrawlDelayError for line 8, got %v", err)
+	}
+	if res := m.Check(robotsTxt, "BarBot", "https://example.com/"); res.CrawlDelayLine != 8 {
+		t.Errorf("Expected CheckResult.CrawlDelayLine 8, got %d", res.CrawlDelayLine)
+	}
+
+	m.IsAllowed("User-agent: *\nDisallow: /\n", "FooBot", "https://example.com/")
+	if d, err := m.CrawlDelayWith(robotsTxt, CrawlDelayOptions{}); d != nil || err != nil {
+		t.Errorf("Expected no crawl-delay, got %v, %v", d, err)
+	}
+}
+
+func TestCheckCrawlDelayRaw(t *testing.T) {
+	m := NewMatcher()
+	defer m.Free()
+	tests := []struct {
+		robotsTxt string
+		raw       string
+		invalid   bool
+	}{
+		{"User-agent: *\nCrawl-delay: fast # please\n", "fast", true},
+		{"User-agent: *\nCrawl-delay: -1\n", "-1", true},
+		{"User-agent: *\nCrawl-delay:\n", "", true},
+		{"User-agent: *\nCrawl-delay: 2.5\n", "2.5", false},
+	}
+	for _, tt := range tests {
+		res := m.Check(tt.robotsTxt, "FooBot", "https://example.com/")
+		if res.CrawlDelay == nil || res.CrawlDelayRaw != tt.raw || res.CrawlDelayInvalid != tt.invalid {
+			t.Errorf("%q: CrawlDelay %v, raw %q, invalid %v", tt.robotsTxt, res.CrawlDelay, res.CrawlDelayRaw, res.CrawlDelayInvalid)
+		}
+	}
+	res := m.Check("User-agent: *\nDisallow: /x\n", "FooBot", "https://example.com/")
+	if res.CrawlDelay != nil || res.CrawlDelayRaw != "" || res.CrawlDelayInvalid {
+		t.Errorf("Expected no crawl-delay, got %+v", res)
+	}
+}
@@ -0,0 +1,125 @@
+package robotstxt
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EnforcementPolicy is the robots.txt rules for a set of known crawlers in
+// a neutral model for WAFs and bot managers, bridging advisory rules and
+// actual enforcement.
+type EnforcementPolicy struct {
+	// Agents are the product tokens, matched case-insensitively as a
+	// substring of the User-Agent header.
+	Agents []string `json:"agents"`
+	// Rules are in precedence order: the first rule matching a request
+	// decides it, and requests matching none are allowed.
+	Rules []EnforcementRule `json:"rules"`
+}
+
+// EnforcementRule is one rule of an EnforcementPolicy.
+type EnforcementRule struct {
+	// Action is "allow" or "block".
+	Action string `json:"action"`
+	// Pattern is the robots.txt pattern and Regex its equivalent, anchored,
+	// over the request path and query.
+	Pattern string `json:"pattern"`
+	Regex   string `json:"regex"`
+}
+
+// Enforcement returns the rules that apply to each known crawler in category
+// (all known crawlers if empty) as EnforcementPolicies, one per distinct
+// rule set. Crawlers without rules are left out, as are unknown agents:
+// enforcing the "*" group on every client would block browsers too.
+func Enforcement(p *ParsedRobots, category BotCategory) []EnforcementPolicy {
+	var out []EnforcementPolicy
+	index := make(map[string]int)
+	for _, a := range KnownAgents(category) {
+		rules := EffectiveRules(p, a.Token)
+		if len(rules) == 0 {
+			continue
+		}
+		var er []EnforcementRule
+		var key strings.Builder
+		for _, r := range rules {
+			re, end := patternRegex(r.Pattern)
+			if end {
+				re += "$"
+			}
+			action := "block"
+			if r.Allow {
+				action = "allow"
+			}
+			er = append(er, EnforcementRule{Action: action, Pattern: r.Pattern, Regex: "^" + re})
+			key.WriteString(action + " " + r.Pattern + "\n")
+		}
+		if i, ok := index[key.String()]; ok {
+			out[i].Agents = append(out[i].Agents, a.Token)
+			continue
+		}
+		index[key.String()] = len(out)
+		out = append(out, EnforcementPolicy{Agents: []string{a.Token}, Rules: er})
+	}
+	return out
+}
+
+// CloudflareRule is a rule of a Cloudflare custom rules ruleset.
+type CloudflareRule struct {
+	Action           string                    `json:"action"`
+	ActionParameters *CloudflareRuleParameters `json:"action_parameters,omitempty"`
+	Expression       string                    `json:"expression"`
+	Description      string                    `json:"description"`
+}
+
+// CloudflareRuleParameters are the parameters of a "skip" rule.
+type CloudflareRuleParameters struct {
+	Ruleset string `json:"ruleset"`
+}
+
+// ExportCloudflare translates Enforcement(p, category) into Cloudflare
+// custom rules, as the JSON body of a ruleset update. Disallow rules block;
+// allow rules skip the rest of the ruleset, which gives them precedence over
+// the shorter disallow rules that follow, and also over the rules generated
+// for other crawlers, so a request naming two crawlers is decided by the
+// first. Plain prefixes use starts_with; patterns with "*" need the matches
+// operator, which is not available on every plan.
+func ExportCloudflare(p *ParsedRobots, category BotCategory) ([]byte, error) {
+	rules := []CloudflareRule{}
+	for _, pol := range Enforcement(p, category) {
+		var agents []string
+		for _, a := range pol.Agents {
+			agents = append(agents, "lower(http.user_agent) contains "+strconv.Quote(strings.ToLower(a)))
+		}
+		who := "(" + strings.Join(agents, " or ") + ")"
+		for _, r := range pol.Rules {
+			cr := CloudflareRule{
+				Action:      "block",
+				Expression:  who + " and " + cloudflareMatch(r),
+				Description: fmt.Sprintf("robots.txt %s %s for %s", r.Action, r.Pattern, strings.Join(pol.Agents, ", ")),
+			}
+			if r.Action == "allow" {
+				cr.Action = "skip"
+				cr.ActionParameters = &CloudflareRuleParameters{Ruleset: "current"}
+			}
+			rules = append(rules, cr)
+		}
+	}
+	return json.MarshalIndent(struct {
+		Rules []CloudflareRule `json:"rules"`
+	}{rules}, "", "  ")
+}
+
+// cloudflareMatch returns the Cloudflare expression matching r's pattern
+// against the request path and query.
+func cloudflareMatch(r EnforcementRule) string {
+	pattern := r.Pattern
+	if strings.Contains(pattern[:len(pattern)-1], "$") || strings.Contains(pattern, "*") {
+		return "(http.request.uri matches " + strconv.Quote(r.Regex) + ")"
+	}
+	if strings.HasSuffix(pattern, "$") {
+		return "(http.request.uri eq " + strconv.Quote(pattern[:len(pattern)-1]) + ")"
+	}
+	return "starts_with(http.request.uri, " + strconv.Quote(pattern) + ")"
+}
@@ -0,0 +1,93 @@
+package robotstxt
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const enforceRobots = `User-agent: GPTBot
+Disallow: /
+
+User-agent: CCBot
+Disallow: /private/
+Allow: /private/press
+Disallow: /*.pdf$
+
+User-agent: *
+Disallow: /tmp/
+`
+
+func TestEnforcement(t *testing.T) {
+	pols := Enforcement(Parse(enforceRobots), CategoryAITraining)
+	if len(pols) < 3 {
+		t.Fatalf("Enforcement = %+v, want GPTBot, CCBot and a shared policy", pols)
+	}
+	if a := pols[0].Agents; len(a) != 1 || a[0] != "GPTBot" {
+		t.Errorf("first policy agents = %v, want [GPTBot]", a)
+	}
+	if r := pols[0].Rules; len(r) != 1 || r[0] != (EnforcementRule{Action: "block", Pattern: "/", Regex: "^/"}) {
+		t.Errorf("GPTBot rules = %+v", r)
+	}
+	var shared *EnforcementPolicy
+	for i := range pols {
+		for _, a := range pols[i].Agents {
+			if a == "ClaudeBot" {
+				shared = &pols[i]
+			}
+		}
+	}
+	if shared == nil || len(shared.Agents) < 2 || len(shared.Rules) != 1 || shared.Rules[0].Pattern != "/tmp/" {
+		t.Errorf("agents falling back to * = %+v, want one shared /tmp/ policy", shared)
+	}
+	if pols := Enforcement(Parse("User-agent: *\nAllow: /\n"), CategorySearch); len(pols) != 1 || pols[0].Rules[0].Action != "allow" {
+		t.Errorf("Enforcement(allow all) = %+v", pols)
+	}
+}
+
+func TestExportCloudflare(t *testing.T) {
+	out, err := ExportCloudflare(Parse(enforceRobots), CategoryAITraining)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rs struct {
+		Rules []CloudflareRule `json:"rules"`
+	}
+	if err := json.Unmarshal(out, &rs); err != nil {
+		t.Fatalf("ExportCloudflare output is not JSON: %v\n%s", err, out)
+	}
+	want := map[string]string{
+		`(lower(http.user_agent) contains "gptbot") and starts_with(http.request.uri, "/")`:             "block",
+		`(lower(http.user_agent) contains "ccbot") and starts_with(http.request.uri, "/private/press")`: "skip",
+		`(lower(http.user_agent) contains "ccbot") and (http.request.uri matches "^/.*\\.pdf$")`:        "block",
+		`(lower(http.user_agent) contains "ccbot") and starts_with(http.request.uri, "/private/")`:      "block",
+	}
+	seen := 0
+	for _, r := range rs.Rules {
+		action, ok := want[r.Expression]
+		if !ok {
+			continue
+		}
+		seen++
+		if r.Action != action {
+			t.Errorf("%s: action %q, want %q", r.Expression, r.Action, action)
+		}
+		if action == "skip" && (r.ActionParameters == nil || r.ActionParameters.Ruleset != "current") {
+			t.Errorf("%s: skip without ruleset parameter", r.Expression)
+		}
+	}
+	if seen != len(want) {
+		t.Errorf("ExportCloudflare matched %d of %d expected rules:\n%s", seen, len(want), out)
+	}
+	if !strings.Contains(string(out), `contains \"claudebot\" or`) {
+		t.Errorf("agents sharing rules are not combined:\n%s", out)
+	}
+
+	out, err = ExportCloudflare(Parse("User-agent: *\nDisallow: /exact$\n"), CategorySearch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `http.request.uri eq \"/exact\"`) {
+		t.Errorf("end-anchored prefix not exported with eq:\n%s", out)
+	}
+}
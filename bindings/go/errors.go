@@ -0,0 +1,108 @@
+package robotstxt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors come in three classes, each with a type that errors.As finds
+// through any wrapping:
+//
+//   - *FetchError: robots.txt could not be retrieved. HTTP error statuses
+//     are results, not errors.
+//   - *ParseError: malformed input at a known position, such as an
+//     overrides file or a custom directive value.
+//   - *BackendError: the native library or a worker process failed.
+//
+// Input validation errors wrap sentinels instead: ErrInvalidURL for URLs
+// and *CrawlDelayError for crawl-delay values.
+
+// ErrUnsupportedScheme is wrapped by the FetchError for a robots.txt URL
+// whose scheme has no fetcher.
+var ErrUnsupportedScheme = errors.New("robotstxt: unsupported scheme")
+
+// FetchError reports a failure to retrieve robots.txt: a transport, TLS,
+// redirect, decoding or scheme error, wrapped in Err.
+type FetchError struct {
+	Origin string
+	// URL is the robots.txt URL requested.
+	URL string
+	Err error
+}
+
+func (e *FetchError) Error() string {
+	return "robotstxt: fetching " + e.URL + ": " + strings.TrimPrefix(e.Err.Error(), "robotstxt: ")
+}
+
+func (e *FetchError) Unwrap() error { return e.Err }
+
+// ParseError reports malformed input, at a position if known. Line and Col
+// are 1-based, or 0 if unknown. File names the input, if it is a file.
+type ParseError struct {
+	File string
+	Line int
+	Col  int
+	// Msg describes the problem; Err, if set, is the underlying error.
+	Msg string
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	var loc string
+	switch {
+	case e.File != "" && e.Line > 0 && e.Col > 0:
+		loc = fmt.Sprintf("%s:%d:%d", e.File, e.Line, e.Col)
+	case e.File != "" && e.Line > 0:
+		loc = fmt.Sprintf("%s:%d", e.File, e.Line)
+	case e.File != "":
+		loc = e.File
+	case e.Line > 0 && e.Col > 0:
+		loc = fmt.Sprintf("line %d, column %d", e.Line, e.Col)
+	case e.Line > 0:
+		loc = fmt.Sprintf("line %d", e.Line)
+	}
+	var parts []string
+	for _, s := range []string{loc, e.Msg, errString(e.Err)} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return "robotstxt: " + strings.Join(parts, ": ")
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// BackendError marks a failure of the matching backend: the native library
+// (an ABI mismatch) or a worker process (a crash or resource limit). It
+// only classifies Err and adds nothing to its message.
+type BackendError struct {
+	// Backend is "cgo" or "worker".
+	Backend string
+	Err     error
+}
+
+func (e *BackendError) Error() string { return e.Err.Error() }
+
+func (e *BackendError) Unwrap() error { return e.Err }
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// position returns the 1-based line and column of byte offset in data.
+func position(data []byte, offset int64) (line, col int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	before := data[:offset]
+	line = 1 + strings.Count(string(before), "\n")
+	col = int(offset) - strings.LastIndexByte(string(before), '\n')
+	return line, col
+}
@@ -0,0 +1,71 @@
+package robotstxt
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFetchError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	_, err = (&Fetcher{}).Fetch(context.Background(), "http://"+addr+"/page")
+	var fe *FetchError
+	if !errors.As(err, &fe) || fe.Origin != "http://"+addr || fe.URL != "http://"+addr+"/robots.txt" {
+		t.Errorf("Fetch(closed port) error = %#v, want *FetchError", err)
+	}
+	_, err = (&Fetcher{}).Fetch(context.Background(), "gopher://example.com/")
+	if !errors.As(err, &fe) || !errors.Is(err, ErrUnsupportedScheme) {
+		t.Errorf("Fetch(gopher) error = %v, want FetchError wrapping ErrUnsupportedScheme", err)
+	}
+	if strings.Count(err.Error(), "robotstxt:") != 1 {
+		t.Errorf("Error() = %q, want a single prefix", err)
+	}
+	if _, err = (&Fetcher{}).Fetch(context.Background(), "not a url"); errors.As(err, &fe) || !errors.Is(err, ErrInvalidURL) {
+		t.Errorf("Fetch(invalid) error = %v, want ErrInvalidURL only", err)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	for _, tc := range []struct {
+		err  *ParseError
+		want string
+	}{
+		{&ParseError{File: "o.json", Line: 3, Col: 7, Err: errors.New("bad")}, "robotstxt: o.json:3:7: bad"},
+		{&ParseError{File: "o.json", Msg: "unknown action"}, "robotstxt: o.json: unknown action"},
+		{&ParseError{Line: 2, Msg: "x-ext", Err: errors.New("bad")}, "robotstxt: line 2: x-ext: bad"},
+		{&ParseError{Line: 2, Col: 5, Msg: "oops"}, "robotstxt: line 2, column 5: oops"},
+	} {
+		if got := tc.err.Error(); got != tc.want {
+			t.Errorf("Error() = %q, want %q", got, tc.want)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "overrides.json")
+	if err := os.WriteFile(path, []byte("{\n  \"https://example.com\": {\"action\": \"deny\"},\n  oops\n}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LoadOverrides(path)
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.File != path || pe.Line != 3 || pe.Col != 3 {
+		t.Errorf("LoadOverrides(bad JSON) error = %#v, want ParseError at 3:3", err)
+	}
+}
+
+func TestBackendError(t *testing.T) {
+	inner := checkABI(ABIVersion, ABIVersion+1)
+	err := error(&BackendError{Backend: "cgo", Err: inner})
+	var be *BackendError
+	if !errors.As(err, &be) || !errors.Is(err, ErrIncompatibleABI) || err.Error() != inner.Error() {
+		t.Errorf("BackendError = %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package robotstxt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EffectiveRules returns the allow and disallow rules that apply to agent in
+// p, ordered by precedence: longest pattern first, allow before disallow on
+// ties, so the first rule matching a URL decides it as the matcher would.
+// Like the matcher, it uses the groups naming agent's product token, or the
+// "*" groups if there are none. Empty patterns, which match nothing, are
+// left out. Each rule's UserAgent is the group's lowercase token.
+func EffectiveRules(p *ParsedRobots, agent string) []Rule {
+	token := agentToken(agent)
+	var named, global []Rule
+	var run []string
+	sawRule := true
+	for _, d := range p.Directives {
+		switch d.Type {
+		case DirectiveUserAgent:
+			if sawRule {
+				run, sawRule = nil, false
+			}
+			run = append(run, agentToken(d.Value))
+		case DirectiveAllow, DirectiveDisallow:
+			sawRule = true
+			if d.Value == "" {
+				continue
+			}
+			for _, a := range run {
+				r := Rule{UserAgent: a, Allow: d.Type == DirectiveAllow, Pattern: d.Value}
+				switch {
+				case a == token && token != "":
+					named = append(named, r)
+				case a == "*":
+					global = append(global, r)
+				}
+			}
+		}
+	}
+	rules := named
+	if len(rules) == 0 && !namesAgent(p, token) {
+		rules = global
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		if len(rules[i].Pattern) != len(rules[j].Pattern) {
+			return len(rules[i].Pattern) > len(rules[j].Pattern)
+		}
+		return rules[i].Allow && !rules[j].Allow
+	})
+	return rules
+}
+
+// namesAgent reports whether p has a user-agent line for token, so that an
+// agent with an empty group does not fall back to "*".
+func namesAgent(p *ParsedRobots, token string) bool {
+	for _, d := range p.Directives {
+		if d.Type == DirectiveUserAgent && token != "" && agentToken(d.Value) == token {
+			return true
+		}
+	}
+	return false
+}
+
+// ExportNginx translates the rules that apply to agent into nginx maps, so
+// a server can enforce what robots.txt only requests. The maps go in the
+// http context; each server block then needs
+//
+//	if ($robots_block_<agent>) { return 403; }
+//
+// Requests are matched by the User-Agent header containing agent's product
+// token (every request for "*") and by $request_uri. Map regexes apply in
+// order, which EffectiveRules makes the matcher's precedence.
+func ExportNginx(p *ParsedRobots, agent string) string {
+	token, name := exportNames(agent)
+	var b strings.Builder
+	fmt.Fprintf(&b, "# robots.txt rules for %s. In each server block add:\n", token)
+	fmt.Fprintf(&b, "#     if ($robots_block_%s) { return 403; }\n", name)
+	fmt.Fprintf(&b, "map $http_user_agent $robots_agent_%s {\n", name)
+	if token == "*" {
+		b.WriteString("    default 1;\n")
+	} else {
+		fmt.Fprintf(&b, "    default 0;\n    \"~*%s\" 1;\n", token)
+	}
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "map $request_uri $robots_deny_%s {\n    default 0;\n", name)
+	for _, r := range EffectiveRules(p, agent) {
+		re, end := patternRegex(r.Pattern)
+		if end {
+			re += "$"
+		}
+		deny := 1
+		if r.Allow {
+			deny = 0
+		}
+		fmt.Fprintf(&b, "    \"~^%s\" %d; # %s\n", nginxQuote(re), deny, ruleString(r))
+	}
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "map \"$robots_agent_%s$robots_deny_%s\" $robots_block_%s {\n", name, name, name)
+	b.WriteString("    default 0;\n    \"11\" 1;\n}\n")
+	return b.String()
+}
+
+// ExportApache translates the rules that apply to agent into mod_rewrite
+// rules for server or virtual host context, ahead of other rewrite rules.
+// Disallowed requests get 403 Forbidden; an allow rule that matches skips
+// the rest of the generated rules without ending rewriting. Requests are
+// matched by the User-Agent header containing agent's product token
+// (every request for "*") and by the path and query of the request line.
+func ExportApache(p *ParsedRobots, agent string) string {
+	token, _ := exportNames(agent)
+	rules := EffectiveRules(p, agent)
+	var b strings.Builder
+	fmt.Fprintf(&b, "# robots.txt rules for %s.\n", token)
+	if len(rules) == 0 {
+		return b.String()
+	}
+	b.WriteString("RewriteEngine On\n")
+	for i, r := range rules {
+		re, end := patternRegex(r.Pattern)
+		if end {
+			re += `\s`
+		}
+		fmt.Fprintf(&b, "# %s\n", ruleString(r))
+		if token != "*" {
+			fmt.Fprintf(&b, "RewriteCond %%{HTTP_USER_AGENT} %s [NC]\n", token)
+		}
+		fmt.Fprintf(&b, "RewriteCond %%{THE_REQUEST} ^[A-Z]+\\s%s\n", re)
+		if r.Allow {
+			fmt.Fprintf(&b, "RewriteRule ^ - [S=%d]\n", len(rules)-i-1)
+		} else {
+			b.WriteString("RewriteRule ^ - [F]\n")
+		}
+	}
+	return b.String()
+}
+
+// exportNames returns agent's product token ("*" for all agents) and a form
+// of it usable in nginx variable names.
+func exportNames(agent string) (token, name string) {
+	token = agentToken(agent)
+	if token == "" || token == "*" {
+		return "*", "all"
+	}
+	return token, strings.ReplaceAll(token, "-", "_")
+}
+
+// patternRegex translates a robots.txt pattern into an unanchored regular
+// expression, reporting whether it ends with the "$" anchor.
+func patternRegex(pattern string) (re string, end bool) {
+	if strings.HasSuffix(pattern, "$") {
+		pattern, end = pattern[:len(pattern)-1], true
+	}
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			b.WriteString(".*")
+		case ' ':
+			b.WriteString(`\x20`)
+		case '.', '+', '?', '(', ')', '[', ']', '{', '}', '|', '^', '$', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), end
+}
+
+// nginxQuote escapes s for a double-quoted nginx string.
+func nginxQuote(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+func ruleString(r Rule) string {
+	if r.Allow {
+		return "Allow: " + r.Pattern
+	}
+	return "Disallow: " + r.Pattern
+}
@@ -0,0 +1,100 @@
+package robotstxt
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const exportRobots = `User-agent: *
+Disallow: /private/
+Allow: /private/public
+Disallow: /*.pdf$
+
+User-agent: Googlebot
+User-agent: Bingbot
+Disallow: /tmp/
+Allow:
+
+User-agent: EmptyBot
+Crawl-delay: 5
+`
+
+func TestEffectiveRules(t *testing.T) {
+	p := Parse(exportRobots)
+	want := []Rule{
+		{UserAgent: "*", Allow: true, Pattern: "/private/public"},
+		{UserAgent: "*", Pattern: "/private/"},
+		{UserAgent: "*", Pattern: "/*.pdf$"},
+	}
+	if got := EffectiveRules(p, "OtherBot/1.0"); !reflect.DeepEqual(got, want) {
+		t.Errorf("EffectiveRules(OtherBot) = %+v, want %+v", got, want)
+	}
+	if got := EffectiveRules(p, "googlebot"); !reflect.DeepEqual(got, []Rule{{UserAgent: "googlebot", Pattern: "/tmp/"}}) {
+		t.Errorf("EffectiveRules(googlebot) = %+v", got)
+	}
+	if got := EffectiveRules(p, "EmptyBot"); len(got) != 0 {
+		t.Errorf("EffectiveRules(EmptyBot) = %+v, want none", got)
+	}
+}
+
+func TestPatternRegexAgreesWithMatcher(t *testing.T) {
+	m := NewMatcher()
+	defer m.Free()
+	for _, pattern := range []string{"/private/", "/*.pdf$", "/a.b?c=1", "/x*y*z", "/exact$"} {
+		re, end := patternRegex(pattern)
+		if end {
+			re += "$"
+		}
+		rx := regexp.MustCompile("^" + re)
+		robots := "User-agent: *\nDisallow: " + pattern + "\n"
+		for _, path := range []string{"/private/x", "/doc.pdf", "/doc.pdf?x", "/a.b?c=1", "/aXb?c=1", "/x1y2z", "/exact", "/exact/more"} {
+			if got, want := rx.MatchString(path), !m.IsAllowed(robots, "bot", "https://example.com"+path); got != want {
+				t.Errorf("pattern %q, path %q: regex match %v, matcher disallows %v", pattern, path, got, want)
+			}
+		}
+	}
+}
+
+func TestExportNginx(t *testing.T) {
+	out := ExportNginx(Parse(exportRobots), "*")
+	for _, want := range []string{
+		"if ($robots_block_all) { return 403; }",
+		"map $http_user_agent $robots_agent_all {\n    default 1;\n}",
+		"    \"~^/private/public\" 0; # Allow: /private/public\n    \"~^/private/\" 1; # Disallow: /private/\n    \"~^/.*\\\\.pdf$\" 1;",
+		"map \"$robots_agent_all$robots_deny_all\" $robots_block_all {",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("ExportNginx(*) lacks %q:\n%s", want, out)
+		}
+	}
+	if out := ExportNginx(Parse(exportRobots), "Googlebot"); !strings.Contains(out, "\"~*googlebot\" 1;") || !strings.Contains(out, "$robots_deny_googlebot") {
+		t.Errorf("ExportNginx(Googlebot):\n%s", out)
+	}
+}
+
+func TestExportApache(t *testing.T) {
+	out := ExportApache(Parse(exportRobots), "*")
+	want := `# robots.txt rules for *.
+RewriteEngine On
+# Allow: /private/public
+RewriteCond %{THE_REQUEST} ^[A-Z]+\s/private/public
+RewriteRule ^ - [S=2]
+# Disallow: /private/
+RewriteCond %{THE_REQUEST} ^[A-Z]+\s/private/
+RewriteRule ^ - [F]
+# Disallow: /*.pdf$
+RewriteCond %{THE_REQUEST} ^[A-Z]+\s/.*\.pdf\s
+RewriteRule ^ - [F]
+`
+	if out != want {
+		t.Errorf("ExportApache(*) =\n%s\nwant\n%s", out, want)
+	}
+	if out := ExportApache(Parse(exportRobots), "Bingbot"); !strings.Contains(out, "RewriteCond %{HTTP_USER_AGENT} bingbot [NC]\n") {
+		t.Errorf("ExportApache(Bingbot):\n%s", out)
+	}
+	if out := ExportApache(Parse(exportRobots), "EmptyBot"); strings.Contains(out, "Rewrite") {
+		t.Errorf("ExportApache(EmptyBot) has rules:\n%s", out)
+	}
+}
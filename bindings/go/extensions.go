@@ -0,0 +1,73 @@
+package robotstxt
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// extension is a registered parser for a custom directive. parse returns
+// the typed value boxed as any.
+type extension struct {
+	parse func(string) (any, error)
+	typ   reflect.Type
+}
+
+var extensions struct {
+	mu sync.RWMutex
+	m  map[string]extension
+}
+
+// RegisterExtension registers a typed parser for the custom directive name,
+// matched case-insensitively against directives the parser does not
+// recognize. Like database/sql.Register, it panics if name is empty or
+// already registered; call it from an init function.
+func RegisterExtension[T any](name string, parse func(string) (T, error)) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if key == "" || parse == nil {
+		panic("robotstxt: RegisterExtension with empty name or nil parser")
+	}
+	extensions.mu.Lock()
+	defer extensions.mu.Unlock()
+	if _, dup := extensions.m[key]; dup {
+		panic("robotstxt: RegisterExtension called twice for " + name)
+	}
+	if extensions.m == nil {
+		extensions.m = make(map[string]extension)
+	}
+	extensions.m[key] = extension{
+		parse: func(v string) (any, error) { return parse(v) },
+		typ:   reflect.TypeOf((*T)(nil)).Elem(),
+	}
+}
+
+// GetExtension returns the values of the custom directive name in p, in
+// file order, parsed by the parser registered with RegisterExtension. It
+// returns an error if name is not registered for type T or if a value does
+// not parse; values before the failing line are still returned.
+func GetExtension[T any](p *ParsedRobots, name string) ([]T, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	extensions.mu.RLock()
+	ext, ok := extensions.m[key]
+	extensions.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("robotstxt: extension %q is not registered", name)
+	}
+	if want := reflect.TypeOf((*T)(nil)).Elem(); ext.typ != want {
+		return nil, fmt.Errorf("robotstxt: extension %q holds %v, not %v", name, ext.typ, want)
+	}
+
+	var values []T
+	for _, d := range p.Directives {
+		if d.Type != DirectiveUnknown || strings.ToLower(d.Key) != key {
+			continue
+		}
+		v, err := ext.parse(d.Value)
+		if err != nil {
+			return values, &ParseError{Line: d.Line, Msg: d.Key, Err: err}
+		}
+		values = append(values, v.(T))
+	}
+	return values, nil
+}
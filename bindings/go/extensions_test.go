@@ -0,0 +1,38 @@
+package robotstxt
+
+import (
+	"strconv"
+	"testing"
+)
+
+func init() {
+	RegisterExtension("X-Max-Pages", strconv.Atoi)
+}
+
+func TestGetExtension(t *testing.T) {
+	p := Parse("User-agent: *\nx-max-pages: 10\nDisallow: /\nX-MAX-PAGES: 20\n")
+	got, err := GetExtension[int](p, "X-Max-Pages")
+	if err != nil || len(got) != 2 || got[0] != 10 || got[1] != 20 {
+		t.Errorf("GetExtension = %v, %v; want [10 20]", got, err)
+	}
+
+	got, err = GetExtension[int](Parse("X-Max-Pages: 5\nX-Max-Pages: many\n"), "x-max-pages")
+	if err == nil || len(got) != 1 || got[0] != 5 {
+		t.Errorf("Expected parse error after [5], got %v, %v", got, err)
+	}
+	if _, err := GetExtension[string](p, "X-Max-Pages"); err == nil {
+		t.Error("Expected error for wrong type")
+	}
+	if _, err := GetExtension[int](p, "X-Unregistered"); err == nil {
+		t.Error("Expected error for unregistered extension")
+	}
+}
+
+func TestRegisterExtensionDuplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for duplicate registration")
+		}
+	}()
+	RegisterExtension("x-max-pages", strconv.Atoi)
+}
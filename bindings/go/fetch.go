@@ -0,0 +1,473 @@
+package robotstxt
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMaxSize is the default cap on fetched robots.txt bodies. RFC 9309
+// requires crawlers to parse at least the first 500 KiB.
+const DefaultMaxSize = 500 << 10
+
+// Fetcher retrieves robots.txt files over HTTP. The zero value is ready to
+// use and is safe for concurrent use. A Fetcher must not be copied after
+// first use.
+type Fetcher struct {
+	// Client is the HTTP client; http.DefaultClient if nil.
+	Client *http.Client
+	// UserAgent is sent in the User-Agent header, if set.
+	UserAgent string
+	// MaxSize caps the body size in bytes; DefaultMaxSize if zero.
+	MaxSize int64
+	// OnTruncate, if set, is called for every response cut at MaxSize, so
+	// operators can log or count oversized files.
+	OnTruncate func(TruncationEvent)
+	// Decoders adds Content-Encoding decoders by lowercase name. gzip and
+	// deflate are built in; br is not, since the standard library has no
+	// brotli reader, so register one here to accept br responses. Without
+	// it, a 2xx response encoded with br fails with an unsupported
+	// Content-Encoding error.
+	Decoders map[string]func(io.Reader) (io.Reader, error)
+	// HTML selects the handling of HTML bodies; see FetchResult.HTML.
+	HTML HTMLPolicy
+	// Locations overrides where robots.txt is fetched for an origin, keyed
+	// by origin as returned by Origin. Values are a path ("/policy/robots")
+	// or an absolute URL. Locate, if set, is consulted for origins without
+	// an entry and returns "" to keep the default. Otherwise /robots.txt is
+	// used.
+	Locations map[string]string
+	Locate    func(origin string) string
+	// Schemes fetches robots.txt over non-HTTP schemes, keyed by lowercase
+	// scheme. ftp uses FTPFetcher unless overridden.
+	Schemes map[string]SchemeFetcher
+	// Prepare, if set, is called on every HTTP request before it is sent,
+	// to add credentials such as a bearer token or signature. It is not
+	// called for redirects; the client copies headers to same-host hops.
+	// Client certificates for mTLS go in the TLSClientConfig of Client's
+	// transport.
+	Prepare func(req *http.Request) error
+	// Mirror, if set, serves every robots.txt from a local directory tree
+	// instead of the network, for offline replay and deterministic tests.
+	// A robots.txt URL maps to host[:port]/path in Mirror, e.g.
+	// os.DirFS("mirror") serves https://example.com/robots.txt from
+	// mirror/example.com/robots.txt; a missing file is status 404.
+	Mirror fs.FS
+	// Clock sets FetchResult.FetchedAt; SystemClock if nil.
+	Clock Clock
+	// Redirects limits the redirects followed over HTTP. A CheckRedirect
+	// set on Client still runs after it.
+	Redirects RedirectPolicy
+
+	// BlockPrivate refuses HTTP and FTP fetches from hosts that resolve to
+	// loopback, private, link-local, multicast or CGNAT addresses, with
+	// ErrBlockedAddress; use it for URLs from untrusted users. Every
+	// address a host resolves to must pass, and the checked address is the
+	// one dialed, so DNS rebinding cannot bypass the check. Schemes
+	// fetchers other than an FTPFetcher without a Dial cannot be checked
+	// and are refused.
+	BlockPrivate bool
+	// PinDNS resolves each host once per Fetch and reuses the answer for
+	// every connection, redirects included.
+	PinDNS bool
+	// Dial, if set, opens HTTP and FTP connections instead of a net.Dialer.
+	// With BlockPrivate or PinDNS it receives checked IP addresses.
+	Dial func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	// inflight holds the fetches in progress, keyed by origin and URL, so
+	// concurrent Fetch calls for the same robots.txt share one request.
+	mu       sync.Mutex
+	inflight map[string]*fetchCall
+}
+
+// fetchCall is a fetch shared by concurrent Fetch calls.
+type fetchCall struct {
+	done chan struct{}
+	res  *FetchResult
+	err  error
+}
+
+// HTMLPolicy says what Fetch does with a 200 response whose body is an HTML
+// page, typically a soft-404 error page.
+type HTMLPolicy int
+
+const (
+	// HTMLParse keeps the body as-is. This is the default.
+	HTMLParse HTMLPolicy = iota
+	// HTMLMissing drops the body, so the origin is treated as having no
+	// robots.txt.
+	HTMLMissing
+)
+
+// TruncationEvent describes a robots.txt body cut at the size cap.
+type TruncationEvent struct {
+	Origin string `json:"origin"`
+	URL    string `json:"url"`
+	// DeclaredLength is the Content-Length header as served, or -1 if
+	// unknown. It is not the body size: with a Content-Encoding it counts
+	// encoded bytes, and the body is not read past TruncatedAt to measure
+	// it.
+	DeclaredLength int64 `json:"declared_length"`
+	// TruncatedAt is the byte offset where the body was cut, i.e. MaxSize.
+	TruncatedAt int64 `json:"truncated_at"`
+}
+
+// FetchResult is a fetched robots.txt response.
+type FetchResult struct {
+	// URL is the robots.txt URL that was requested: /robots.txt on the
+	// origin unless overridden by Fetcher.Locations or Fetcher.Locate.
+	URL string
+	// Origin is the scheme://host[:port] the robots.txt applies to, in the
+	// canonical form returned by Origin.
+	Origin     string
+	StatusCode int
+	// Body holds at most MaxSize bytes of the decoded response body.
+	Body []byte
+	// ContentEncoding is the encoding the body was served with, such as
+	// "gzip", or a list in the order applied, such as "deflate, gzip", or
+	// "" for none. Gzip bodies without a Content-Encoding header are
+	// detected and decoded too.
+	ContentEncoding string
+	// ContentType is the Content-Type header as served.
+	ContentType string
+	// HTML is true if the body is an HTML page rather than robots.txt, as
+	// detected by content sniffing; the Content-Type header alone is not
+	// trusted. With HTMLMissing, Body is then empty.
+	HTML bool
+	// Truncated is true if the response was longer than MaxSize and Body
+	// holds only its first MaxSize bytes.
+	Truncated bool
+	FetchedAt time.Time
+}
+
+// Fetch retrieves /robots.txt for the origin of rawURL. Non-2xx responses
+// are returned as results, not errors. Non-HTTP schemes go through Schemes,
+// and every scheme through Mirror if set, with StatusCode 200 for a body
+// and 404 for a missing file. Retrieval failures are *FetchErrors.
+//
+// Concurrent calls for the same robots.txt are coalesced into one request,
+// so a crawl starting many workers on a new host fetches it once. Waiting
+// callers get their own copy of the result and stop waiting when their ctx
+// is done.
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*FetchResult, error) {
+	origin, err := Origin(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	robotsURL, err := f.location(origin)
+	if err != nil {
+		return nil, err
+	}
+	res, err := f.shared(ctx, origin, robotsURL)
+	if err != nil {
+		var fe *FetchError
+		if !errors.As(err, &fe) {
+			err = &FetchError{Origin: origin, URL: robotsURL, Err: err}
+		}
+		return nil, err
+	}
+	return res, nil
+}
+
+// shared runs fetch once for concurrent callers with the same origin and
+// robotsURL. A caller whose ctx is still live fetches again if the shared
+// fetch was cut short by the ctx of the caller that started it.
+func (f *Fetcher) shared(ctx context.Context, origin, robotsURL string) (*FetchResult, error) {
+	key := origin + " " + robotsURL
+	for {
+		f.mu.Lock()
+		c, ok := f.inflight[key]
+		if !ok {
+			c = &fetchCall{done: make(chan struct{})}
+			if f.inflight == nil {
+				f.inflight = make(map[string]*fetchCall)
+			}
+			f.inflight[key] = c
+			f.mu.Unlock()
+			c.res, c.err = f.fetch(ctx, origin, robotsURL)
+			f.mu.Lock()
+			delete(f.inflight, key)
+			f.mu.Unlock()
+			close(c.done)
+		} else {
+			f.mu.Unlock()
+			select {
+			case <-c.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			if c.err != nil && ctx.Err() == nil &&
+				(errors.Is(c.err, context.Canceled) || errors.Is(c.err, context.DeadlineExceeded)) {
+				continue
+			}
+		}
+		if c.err != nil {
+			return nil, c.err
+		}
+		res := *c.res
+		if res.Body != nil {
+			res.Body = append([]byte{}, res.Body...)
+		}
+		return &res, nil
+	}
+}
+
+// fetch retrieves robotsURL for origin.
+func (f *Fetcher) fetch(ctx context.Context, origin, robotsURL string) (*FetchResult, error) {
+	res := &FetchResult{URL: robotsURL, Origin: origin}
+	var err error
+	var body io.ReadCloser
+	size := int64(-1)
+	scheme := strings.ToLower(robotsURL[:strings.IndexByte(robotsURL, ':')])
+	if f.Mirror == nil && (scheme == "http" || scheme == "https") {
+		var resp *http.Response
+		if resp, err = f.get(ctx, robotsURL); err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		r, encoding, err := f.decode(resp)
+		if err != nil {
+			if resp.StatusCode < 200 || resp.StatusCode > 299 {
+				// Only the status of an error response matters; an
+				// undecodable body is as good as an empty one.
+				r, err = strings.NewReader(""), nil
+			} else {
+				return nil, err
+			}
+		}
+		body, size = io.NopCloser(r), resp.ContentLength
+		res.StatusCode = resp.StatusCode
+		res.ContentEncoding = encoding
+		res.ContentType = resp.Header.Get("Content-Type")
+	} else {
+		sf, ok := f.Schemes[scheme]
+		switch {
+		case f.Mirror != nil:
+			sf, ok = mirrorFetcher{f.Mirror}, true
+		case !ok && scheme == "ftp":
+			sf, ok = FTPFetcher{}, true
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrUnsupportedScheme, scheme)
+		}
+		if sf, err = f.guardScheme(sf, scheme); err != nil {
+			return nil, err
+		}
+		body, err = sf.Open(ctx, robotsURL)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			res.StatusCode, body = http.StatusNotFound, io.NopCloser(strings.NewReader(""))
+		case err != nil:
+			return nil, err
+		default:
+			res.StatusCode = http.StatusOK
+		}
+		defer body.Close()
+	}
+
+	maxSize := f.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	res.Body, err = io.ReadAll(io.LimitReader(body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(res.Body)) > maxSize {
+		res.Body, res.Truncated = res.Body[:maxSize], true
+		if f.OnTruncate != nil {
+			f.OnTruncate(TruncationEvent{
+				Origin:         origin,
+				URL:            robotsURL,
+				DeclaredLength: size,
+				TruncatedAt:    maxSize,
+			})
+		}
+	}
+	res.HTML = looksLikeHTML(res.Body)
+	if res.HTML && f.HTML == HTMLMissing {
+		res.Body = nil
+	}
+	res.FetchedAt = clockOr(f.Clock).Now()
+	return res, nil
+}
+
+// guardScheme applies f's dial guards to sf. FTPFetchers without a Dial of
+// their own dial through them; other fetchers open connections the guards
+// cannot see, so BlockPrivate refuses them.
+func (f *Fetcher) guardScheme(sf SchemeFetcher, scheme string) (SchemeFetcher, error) {
+	if f.Mirror != nil || !f.guarded() {
+		return sf, nil
+	}
+	if ftp, ok := sf.(FTPFetcher); ok && ftp.Dial == nil {
+		ftp.Dial = (&guardedDialer{f: f, pins: make(map[string][]net.IP)}).dial
+		return ftp, nil
+	}
+	if f.BlockPrivate {
+		return nil, fmt.Errorf("%w: %s fetcher cannot be checked", ErrBlockedAddress, scheme)
+	}
+	return sf, nil
+}
+
+// get sends the HTTP request for robotsURL.
+func (f *Fetcher) get(ctx context.Context, robotsURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, robotsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if f.UserAgent != "" {
+		req.Header.Set("User-Agent", f.UserAgent)
+	}
+	// Setting Accept-Encoding stops the transport from decoding gzip
+	// transparently, so every encoding goes through decode and the size cap
+	// applies to decoded bytes.
+	req.Header.Set("Accept-Encoding", f.acceptEncoding())
+	if f.Prepare != nil {
+		if err := f.Prepare(req); err != nil {
+			return nil, fmt.Errorf("robotstxt: prepare request: %w", err)
+		}
+	}
+
+	client := *http.DefaultClient
+	if f.Client != nil {
+		client = *f.Client
+	}
+	if f.guarded() {
+		t, err := f.transport(client.Transport)
+		if err != nil {
+			return nil, err
+		}
+		client.Transport = t
+	}
+	next := client.CheckRedirect
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if err := f.Redirects.check(req, via); err != nil || next == nil {
+			return err
+		}
+		return next(req, via)
+	}
+	return client.Do(req)
+}
+
+// location returns the robots.txt URL for origin.
+func (f *Fetcher) location(origin string) (string, error) {
+	loc, ok := f.Locations[origin]
+	if !ok && f.Locate != nil {
+		loc = f.Locate(origin)
+	}
+	switch {
+	case loc == "":
+		return origin + "/robots.txt", nil
+	case strings.HasPrefix(loc, "/"):
+		return origin + loc, nil
+	}
+	if _, err := parseAbsoluteURL(loc); err != nil {
+		return "", fmt.Errorf("robotstxt: robots.txt location for %s: %w", origin, err)
+	}
+	return loc, nil
+}
+
+// acceptEncoding returns the Accept-Encoding header listing the built-in
+// and configured decoders.
+func (f *Fetcher) acceptEncoding() string {
+	names := []string{"gzip", "deflate"}
+	extra := make([]string, 0, len(f.Decoders))
+	for name := range f.Decoders {
+		if name != "gzip" && name != "deflate" {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	return strings.Join(append(names, extra...), ", ")
+}
+
+// decode returns a reader for the decoded body of resp and the encodings it
+// was served with. A list of encodings is undone last to first. An empty
+// body decodes to an empty body whatever its encoding.
+func (f *Fetcher) decode(resp *http.Response) (io.Reader, string, error) {
+	var encs []string
+	for _, enc := range strings.Split(resp.Header.Get("Content-Encoding"), ",") {
+		if enc = strings.ToLower(strings.TrimSpace(enc)); enc != "" && enc != "identity" {
+			encs = append(encs, enc)
+		}
+	}
+	br := bufio.NewReader(resp.Body)
+	if len(encs) == 0 {
+		// Some servers send gzip without saying so.
+		if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+			encs = []string{"gzip"}
+		} else {
+			return br, "", nil
+		}
+	}
+	encoding := strings.Join(encs, ", ")
+	if _, err := br.Peek(1); err == io.EOF {
+		return br, encoding, nil
+	}
+	var r io.Reader = br
+	for i := len(encs) - 1; i >= 0; i-- {
+		var err error
+		if r, err = f.decodeOne(encs[i], r); err != nil {
+			return nil, encoding, err
+		}
+	}
+	return r, encoding, nil
+}
+
+// decodeOne returns a reader undoing the content encoding enc of r.
+func (f *Fetcher) decodeOne(enc string, r io.Reader) (io.Reader, error) {
+	if dec, ok := f.Decoders[enc]; ok {
+		d, err := dec(r)
+		if err != nil {
+			return nil, fmt.Errorf("robotstxt: %s body: %w", enc, err)
+		}
+		return d, nil
+	}
+	switch enc {
+	case "gzip", "x-gzip":
+		d, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("robotstxt: gzip body: %w", err)
+		}
+		return d, nil
+	case "deflate":
+		// HTTP deflate is zlib-wrapped, but some servers send raw deflate.
+		br := bufio.NewReader(r)
+		if h, _ := br.Peek(2); len(h) == 2 && h[0]&0x0f == 8 && (uint16(h[0])<<8|uint16(h[1]))%31 == 0 {
+			d, err := zlib.NewReader(br)
+			if err != nil {
+				return nil, fmt.Errorf("robotstxt: deflate body: %w", err)
+			}
+			return d, nil
+		}
+		return flate.NewReader(br), nil
+	}
+	return nil, fmt.Errorf("robotstxt: unsupported Content-Encoding %q", enc)
+}
+
+// looksLikeHTML reports whether body sniffs as an HTML document.
+func looksLikeHTML(body []byte) bool {
+	body = bytes.TrimPrefix(body, []byte("\ufeff"))
+	return strings.HasPrefix(http.DetectContentType(body), "text/html")
+}
+
+// AppliesTo reports whether the fetched robots.txt governs rawURL, i.e.
+// whether rawURL has the same scheme, host and port. An http robots.txt
+// does not apply to https URLs, nor to other ports on the same host.
+func (r *FetchResult) AppliesTo(rawURL string) bool {
+	origin, err := Origin(rawURL)
+	return err == nil && origin == r.Origin
+}
@@ -0,0 +1,418 @@
+package robotstxt
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		if ua := r.Header.Get("User-Agent"); ua != "TestBot/1.0" {
+			t.Errorf("Unexpected User-Agent %q", ua)
+		}
+		w.Write([]byte("User-agent: *\nDisallow: /private/\n" + strings.Repeat("#", 100)))
+	}))
+	defer srv.Close()
+
+	var events []TruncationEvent
+	f := &Fetcher{UserAgent: "TestBot/1.0", MaxSize: 40, OnTruncate: func(e TruncationEvent) { events = append(events, e) }}
+	res, err := f.Fetch(context.Background(), srv.URL+"/some/page?q=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.URL != srv.URL+"/robots.txt" {
+		t.Errorf("Unexpected URL %q", res.URL)
+	}
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("Unexpected status %d", res.StatusCode)
+	}
+	if len(res.Body) != 40 {
+		t.Errorf("Expected body capped at 40 bytes, got %d", len(res.Body))
+	}
+	if res.Origin != srv.URL {
+		t.Errorf("Unexpected origin %q", res.Origin)
+	}
+	if !res.Truncated {
+		t.Error("Expected Truncated")
+	}
+	want := TruncationEvent{Origin: srv.URL, URL: srv.URL + "/robots.txt", DeclaredLength: 134, TruncatedAt: 40}
+	if len(events) != 1 || events[0] != want {
+		t.Errorf("Truncation events = %+v, want %+v", events, want)
+	}
+
+	f.MaxSize = 134
+	if res, err = f.Fetch(context.Background(), srv.URL); err != nil || res.Truncated || len(events) != 1 {
+		t.Errorf("Expected a body of exactly MaxSize not to be truncated: %v, %+v", err, res)
+	}
+}
+
+func TestFetchResultAppliesTo(t *testing.T) {
+	res := &FetchResult{Origin: "http://example.com:8080"}
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"http://example.com:8080/page", true},
+		{"http://EXAMPLE.com:8080/", true},
+		{"http://example.com/page", false},
+		{"https://example.com:8080/page", false},
+		{"http://sub.example.com:8080/", false},
+		{"not a url", false},
+	}
+	for _, tt := range tests {
+		if got := res.AppliesTo(tt.url); got != tt.want {
+			t.Errorf("AppliesTo(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestFetchInvalidURL(t *testing.T) {
+	var f Fetcher
+	if _, err := f.Fetch(context.Background(), "/relative/path"); err == nil {
+		t.Error("Expected error for relative URL")
+	}
+}
+
+func TestFetchContentEncoding(t *testing.T) {
+	const body = "User-agent: *\nDisallow: /private/\n"
+	compress := func(w func(io.Writer) io.WriteCloser, data string) []byte {
+		var b bytes.Buffer
+		zw := w(&b)
+		zw.Write([]byte(data))
+		zw.Close()
+		return b.Bytes()
+	}
+	gz := func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }
+	zl := func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }
+	raw := func(w io.Writer) io.WriteCloser { fw, _ := flate.NewWriter(w, flate.BestSpeed); return fw }
+
+	tests := []struct {
+		name     string
+		encoding string
+		data     []byte
+		want     string
+	}{
+		{"gzip", "gzip", compress(gz, body), "gzip"},
+		{"unlabeled gzip", "", compress(gz, body), "gzip"},
+		{"zlib deflate", "deflate", compress(zl, body), "deflate"},
+		{"raw deflate", "deflate", compress(raw, body), "deflate"},
+		{"custom", "x-base64", []byte(base64.StdEncoding.EncodeToString([]byte(body))), "x-base64"},
+		{"list", "deflate, GZIP", compress(gz, string(compress(zl, body))), "deflate, gzip"},
+		{"custom list", "x-base64, gzip", compress(gz, base64.StdEncoding.EncodeToString([]byte(body))), "x-base64, gzip"},
+		{"identity in list", "identity, gzip", compress(gz, body), "gzip"},
+		{"identity", "", []byte(body), ""},
+	}
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ae := r.Header.Get("Accept-Encoding"); ae != "gzip, deflate, x-base64" {
+				t.Errorf("Accept-Encoding = %q", ae)
+			}
+			if tt.encoding != "" {
+				w.Header().Set("Content-Encoding", tt.encoding)
+			}
+			w.Write(tt.data)
+		}))
+		f := &Fetcher{Decoders: map[string]func(io.Reader) (io.Reader, error){
+			"x-base64": func(r io.Reader) (io.Reader, error) {
+				return base64.NewDecoder(base64.StdEncoding, r), nil
+			},
+		}}
+		res, err := f.Fetch(context.Background(), srv.URL)
+		srv.Close()
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if string(res.Body) != body || res.ContentEncoding != tt.want {
+			t.Errorf("%s: got %q encoded %q, want encoding %q", tt.name, res.Body, res.ContentEncoding, tt.want)
+		}
+	}
+}
+
+func TestFetchDecompressionCap(t *testing.T) {
+	var bomb bytes.Buffer
+	zw := gzip.NewWriter(&bomb)
+	zw.Write(make([]byte, 10<<20))
+	zw.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(bomb.Bytes())
+	}))
+	defer srv.Close()
+
+	res, err := (&Fetcher{MaxSize: 1000}).Fetch(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Body) != 1000 || !res.Truncated {
+		t.Errorf("Expected decoded body capped at 1000 bytes, got %d (truncated %v)", len(res.Body), res.Truncated)
+	}
+
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "br")
+		w.Write([]byte("x"))
+	})
+	if _, err := (&Fetcher{}).Fetch(context.Background(), srv.URL); err == nil {
+		t.Error("Expected error for unsupported encoding")
+	}
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip, br")
+		w.Write([]byte("x"))
+	})
+	if _, err := (&Fetcher{}).Fetch(context.Background(), srv.URL); err == nil || !strings.Contains(err.Error(), `"br"`) {
+		t.Errorf("Expected br to be unsupported without a decoder, got %v", err)
+	}
+}
+
+func TestFetchEncodedErrorPages(t *testing.T) {
+	var status int
+	var encoding, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", encoding)
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		status   int
+		encoding string
+		body     string
+	}{
+		{http.StatusNotFound, "gzip", ""},
+		{http.StatusServiceUnavailable, "gzip", "Service Unavailable"},
+		{http.StatusNotFound, "br", "x"},
+		{http.StatusOK, "gzip", ""},
+		{http.StatusOK, "br", ""},
+	}
+	for _, tt := range tests {
+		status, encoding, body = tt.status, tt.encoding, tt.body
+		res, err := (&Fetcher{}).Fetch(context.Background(), srv.URL)
+		if err != nil {
+			t.Errorf("%d %s %q: %v", tt.status, tt.encoding, tt.body, err)
+			continue
+		}
+		if res.StatusCode != tt.status || len(res.Body) != 0 || res.ContentEncoding != tt.encoding {
+			t.Errorf("%d %s %q: got %+v", tt.status, tt.encoding, tt.body, res)
+		}
+	}
+}
+
+func TestFetchHTMLDetection(t *testing.T) {
+	page := "\n<!DOCTYPE html>\n<html><body><h1>Not Found</h1></body></html>\n"
+	robots := "User-agent: *\nDisallow: /private/\n"
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Deliberately mislabeled in both directions.
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		body     string
+		policy   HTMLPolicy
+		wantHTML bool
+		wantBody string
+	}{
+		{page, HTMLParse, true, page},
+		{page, HTMLMissing, true, ""},
+		{"\ufeff<html><head><title>x</title></head></html>", HTMLMissing, true, ""},
+		{robots, HTMLMissing, false, robots},
+	}
+	for _, tt := range tests {
+		body = tt.body
+		res, err := (&Fetcher{HTML: tt.policy}).Fetch(context.Background(), srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.HTML != tt.wantHTML || string(res.Body) != tt.wantBody || res.ContentType != "text/html" {
+			t.Errorf("Fetch of %q with policy %d: HTML %v, body %q", tt.body, tt.policy, res.HTML, res.Body)
+		}
+	}
+}
+
+func TestFetchLocations(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+	}))
+	defer srv.Close()
+
+	f := &Fetcher{
+		Locations: map[string]string{srv.URL: "/policies/robots"},
+		Locate: func(origin string) string {
+			if origin == "http://other.example" {
+				return srv.URL + "/shared/robots.txt"
+			}
+			return ""
+		},
+	}
+	res, err := f.Fetch(context.Background(), srv.URL+"/page")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.URL != srv.URL+"/policies/robots" || res.Origin != srv.URL {
+		t.Errorf("Unexpected URL %q, origin %q", res.URL, res.Origin)
+	}
+	if res, err = f.Fetch(context.Background(), "http://other.example/x"); err != nil {
+		t.Fatal(err)
+	}
+	if res.URL != srv.URL+"/shared/robots.txt" || res.Origin != "http://other.example" {
+		t.Errorf("Unexpected URL %q, origin %q", res.URL, res.Origin)
+	}
+	f.Locations = nil
+	if _, err = f.Fetch(context.Background(), srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"/policies/robots", "/shared/robots.txt", "/robots.txt"}; strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Errorf("Requested %v, want %v", paths, want)
+	}
+
+	f.Locations = map[string]string{srv.URL: "not a url"}
+	if _, err = f.Fetch(context.Background(), srv.URL); err == nil {
+		t.Error("Expected error for invalid location")
+	}
+}
+
+func TestFetchPrepare(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("User-agent: *\nDisallow: /intranet/\n"))
+	}))
+	defer srv.Close()
+
+	f := &Fetcher{}
+	if res, err := f.Fetch(context.Background(), srv.URL); err != nil || res.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Fetch without credentials = %+v, %v", res, err)
+	}
+	f.Prepare = func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer s3cret")
+		return nil
+	}
+	if res, err := f.Fetch(context.Background(), srv.URL); err != nil || res.StatusCode != http.StatusOK {
+		t.Errorf("Fetch with Prepare = %+v, %v", res, err)
+	}
+	f.Prepare = func(*http.Request) error { return io.ErrUnexpectedEOF }
+	if _, err := f.Fetch(context.Background(), srv.URL); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Fetch with failing Prepare error = %v", err)
+	}
+}
+
+func TestFetchCoalesced(t *testing.T) {
+	const body = "User-agent: *\nDisallow: /private/\n"
+	var hits int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		<-release
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	f := &Fetcher{}
+
+	const workers = 20
+	results := make(chan *FetchResult, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			res, err := f.Fetch(context.Background(), fmt.Sprintf("%s/page/%d", srv.URL, i))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			results <- res
+		}(i)
+	}
+	for atomic.LoadInt32(&hits) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := f.Fetch(ctx, srv.URL); !errors.Is(err, context.Canceled) {
+		t.Errorf("Fetch with a cancelled waiter error = %v, want context.Canceled", err)
+	}
+
+	close(release)
+	wg.Wait()
+	close(results)
+	if n := atomic.LoadInt32(&hits); n != 1 {
+		t.Errorf("Concurrent fetches sent %d requests, want 1", n)
+	}
+	var first *FetchResult
+	for res := range results {
+		if string(res.Body) != body || res.StatusCode != http.StatusOK {
+			t.Errorf("Unexpected result %+v", res)
+		}
+		if first == nil {
+			first = res
+		} else if first == res || &first.Body[0] == &res.Body[0] {
+			t.Error("Expected every caller to get its own copy of the result")
+		}
+	}
+
+	if _, err := f.Fetch(context.Background(), srv.URL); err != nil || atomic.LoadInt32(&hits) != 2 {
+		t.Errorf("Fetch after the shared one: %v, %d requests", err, atomic.LoadInt32(&hits))
+	}
+}
+
+func TestFetchCoalescedLeaderCancelled(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte("User-agent: *\n"))
+	}))
+	defer srv.Close()
+	f := &Fetcher{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leader := make(chan error, 1)
+	go func() {
+		_, err := f.Fetch(ctx, srv.URL)
+		leader <- err
+	}()
+	for atomic.LoadInt32(&hits) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	waiter := make(chan error, 1)
+	go func() {
+		_, err := f.Fetch(context.Background(), srv.URL)
+		waiter <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if err := <-leader; !errors.Is(err, context.Canceled) {
+		t.Errorf("Leader error = %v, want context.Canceled", err)
+	}
+	if err := <-waiter; err != nil {
+		t.Errorf("Waiter error = %v, want a fresh fetch", err)
+	}
+}
@@ -0,0 +1,190 @@
+package robotstxt
+
+import (
+	"strings"
+)
+
+// FormatOptions controls Format.
+type FormatOptions struct {
+	// Align pads directive names within each group so values line up.
+	Align bool
+	// Indent is prefixed to the rules of a group (every directive except
+	// user-agent and sitemap lines), e.g. "  ".
+	Indent string
+}
+
+// canonicalKeys are the directive names Format writes for known directives.
+var canonicalKeys = map[DirectiveType]string{
+	DirectiveUserAgent:     "User-agent",
+	DirectiveAllow:         "Allow",
+	DirectiveDisallow:      "Disallow",
+	DirectiveSitemap:       "Sitemap",
+	DirectiveCrawlDelay:    "Crawl-delay",
+	DirectiveRequestRate:   "Request-rate",
+	DirectiveContentSignal: "Content-Signal",
+}
+
+// formatItem is one output line: a directive, or a line the parser ignores,
+// together with the comment lines directly above it. A detached comment
+// block (comments followed by a blank line) is an item with block set.
+type formatItem struct {
+	comments []string // full comment lines, "#" included
+	block    bool
+	typ      DirectiveType
+	key      string // "" for lines that are not directives
+	value    string
+	comment  string // normalized trailing comment, "#" included
+	raw      string // the trimmed line, for non-directives
+}
+
+// Format returns content in canonical form: one blank line between groups
+// and before sitemaps, canonical directive casing (including typo fixes such
+// as "disalow"), "Key: value" spacing and LF line endings. Comment lines stay
+// attached to the directive below them, trailing comments stay on their line,
+// and comment blocks followed by a blank line are kept as separate blocks.
+// Lines the parser ignores are kept verbatim.
+func Format(content string, opts FormatOptions) string {
+	directives := make(map[int]Directive)
+	for _, d := range Parse(content).Directives {
+		directives[d.Line] = d
+	}
+
+	var items []formatItem
+	var pending []string
+	for i, line := range splitLines(strings.TrimPrefix(content, "\ufeff")) {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			if len(pending) > 0 {
+				items = append(items, formatItem{comments: pending, block: true})
+				pending = nil
+			}
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			pending = append(pending, normalizeComment(trimmed))
+			continue
+		}
+		it := formatItem{comments: pending, raw: trimmed}
+		pending = nil
+		if d, ok := directives[i+1]; ok {
+			it.typ = d.Type
+			it.key = canonicalKeys[d.Type]
+			if it.key == "" {
+				it.key = d.Key
+			}
+			it.value = directiveValue(line)
+			if j := strings.IndexByte(line, '#'); j >= 0 {
+				it.comment = normalizeComment(line[j:])
+			}
+		}
+		items = append(items, it)
+	}
+	if len(pending) > 0 {
+		items = append(items, formatItem{comments: pending, block: true})
+	}
+
+	// Split directives into sections separated by blank lines, and size the
+	// alignment column of each.
+	section := make([]int, len(items))
+	var widths []int
+	var prev *formatItem
+	for i := range items {
+		it := &items[i]
+		if it.block {
+			prev = nil
+			continue
+		}
+		if prev == nil || startsSection(*prev, *it) {
+			widths = append(widths, 0)
+		}
+		section[i] = len(widths) - 1
+		if w := len(it.prefix(opts) + it.key); w > widths[section[i]] {
+			widths[section[i]] = w
+		}
+		prev = it
+	}
+
+	var b strings.Builder
+	prev = nil
+	for i := range items {
+		it := &items[i]
+		if it.block {
+			blankLine(&b)
+			for _, c := range it.comments {
+				b.WriteString(c + "\n")
+			}
+			blankLine(&b)
+			prev = nil
+			continue
+		}
+		if prev != nil && startsSection(*prev, *it) {
+			blankLine(&b)
+		}
+		prev = it
+		for _, c := range it.comments {
+			b.WriteString(c + "\n")
+		}
+		if it.key == "" {
+			b.WriteString(it.raw + "\n")
+			continue
+		}
+		name := it.prefix(opts) + it.key
+		b.WriteString(name + ":")
+		if it.value != "" || it.comment != "" {
+			pad := 1
+			if opts.Align {
+				pad += widths[section[i]] - len(name)
+			}
+			b.WriteString(strings.Repeat(" ", pad))
+		}
+		b.WriteString(it.value)
+		if it.comment != "" {
+			if it.value != "" {
+				b.WriteByte(' ')
+			}
+			b.WriteString(it.comment)
+		}
+		b.WriteByte('\n')
+	}
+	out := strings.TrimRight(b.String(), "\n")
+	if out == "" {
+		return ""
+	}
+	return out + "\n"
+}
+
+// prefix returns the indentation of the item's directive.
+func (it *formatItem) prefix(opts FormatOptions) string {
+	if it.typ == DirectiveUserAgent || it.typ == DirectiveSitemap {
+		return ""
+	}
+	return opts.Indent
+}
+
+// startsSection reports whether cur begins a new group or the sitemap block
+// and so gets a blank line before it.
+func startsSection(prev, cur formatItem) bool {
+	switch cur.typ {
+	case DirectiveUserAgent:
+		return prev.typ != DirectiveUserAgent
+	case DirectiveSitemap:
+		return prev.typ != DirectiveSitemap
+	}
+	return false
+}
+
+// normalizeComment returns a "#" comment with exactly one space after the
+// hash, or just "#" if it is empty.
+func normalizeComment(c string) string {
+	if t := strings.TrimSpace(strings.TrimPrefix(c, "#")); t != "" {
+		return "# " + t
+	}
+	return "#"
+}
+
+// blankLine ends b with a blank line unless it is empty or already does.
+func blankLine(b *strings.Builder) {
+	if s := b.String(); s != "" && !strings.HasSuffix(s, "\n\n") {
+		b.WriteByte('\n')
+	}
+}
@@ -0,0 +1,57 @@
+package robotstxt
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	in := "# Site robots\r\n" +
+		"\r\n" +
+		"user-agent:FooBot\r\n" +
+		"USER-AGENT :  BarBot\r\n" +
+		"# keep private out\r\n" +
+		"disalow: /private/   #  typo fixed\r\n" +
+		"allow:/public\r\n" +
+		"\r\n" +
+		"\r\n" +
+		"sitemap: https://example.com/sitemap.xml\r\n" +
+		"User-agent: *\r\n" +
+		"crawl-delay: 5\r\n" +
+		"x-custom:  value\r\n" +
+		"garbage line here\r\n" +
+		"# trailing\r\n"
+	want := "# Site robots\n" +
+		"\n" +
+		"User-agent: FooBot\n" +
+		"User-agent: BarBot\n" +
+		"# keep private out\n" +
+		"Disallow: /private/ # typo fixed\n" +
+		"Allow: /public\n" +
+		"\n" +
+		"Sitemap: https://example.com/sitemap.xml\n" +
+		"\n" +
+		"User-agent: *\n" +
+		"Crawl-delay: 5\n" +
+		"x-custom: value\n" +
+		"garbage line here\n" +
+		"\n" +
+		"# trailing\n"
+	if got := Format(in, FormatOptions{}); got != want {
+		t.Errorf("Format() =\n%s\nwant\n%s", got, want)
+	}
+	if got := Format(want, FormatOptions{}); got != want {
+		t.Errorf("Format is not idempotent:\n%s", got)
+	}
+}
+
+func TestFormatAlign(t *testing.T) {
+	in := "User-agent: *\nDisallow: /a\nAllow: /b\nCrawl-delay: 1\n"
+	want := "User-agent:    *\n" +
+		"  Disallow:    /a\n" +
+		"  Allow:       /b\n" +
+		"  Crawl-delay: 1\n"
+	if got := Format(in, FormatOptions{Align: true, Indent: "  "}); got != want {
+		t.Errorf("Format() =\n%s\nwant\n%s", got, want)
+	}
+	if got := Format("", FormatOptions{}); got != "" {
+		t.Errorf("Expected empty output, got %q", got)
+	}
+}
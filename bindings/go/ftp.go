@@ -0,0 +1,189 @@
+package robotstxt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SchemeFetcher retrieves robots.txt over a non-HTTP scheme, for
+// Fetcher.Schemes.
+type SchemeFetcher interface {
+	// Open returns the body of the file at robotsURL. It returns an error
+	// wrapping fs.ErrNotExist if there is none.
+	Open(ctx context.Context, robotsURL string) (io.ReadCloser, error)
+}
+
+// FTPFetcher is a SchemeFetcher for ftp:// URLs using passive mode. The zero
+// value logs in anonymously. Credentials in the robots.txt URL take
+// precedence over User and Password; Fetcher.Fetch drops those of the URL
+// it is given along with the rest of its path, so they can only come from
+// Fetcher.Locations or Fetcher.Locate.
+type FTPFetcher struct {
+	User     string
+	Password string
+	// Dial, if set, opens the control and data connections instead of a
+	// net.Dialer.
+	Dial func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// Open implements SchemeFetcher.
+func (f FTPFetcher) Open(ctx context.Context, robotsURL string) (io.ReadCloser, error) {
+	u, err := parseAbsoluteURL(robotsURL)
+	if err != nil {
+		return nil, err
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "21")
+	}
+	user, pass := f.User, f.Password
+	if user == "" {
+		user, pass = "anonymous", "anonymous@"
+	}
+	if u.User != nil {
+		user = u.User.Username()
+		pass, _ = u.User.Password()
+	}
+	// Decoded %0d%0a would end the command and start another.
+	for _, s := range []string{user, pass, u.Path} {
+		if strings.ContainsAny(s, "\r\n\x00") {
+			return nil, fmt.Errorf("robotstxt: ftp URL %q contains CR, LF or NUL", robotsURL)
+		}
+	}
+
+	dial := f.Dial
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	conn, err := dial(ctx, "tcp", host)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	c := &ftpConn{Conn: textproto.NewConn(conn), raw: conn}
+	body, err := c.retrieve(ctx, dial, u, user, pass)
+	if err != nil {
+		if c.data != nil {
+			c.data.Close()
+		}
+		conn.Close()
+		return nil, err
+	}
+	return body, nil
+}
+
+// ftpConn is an FTP control connection.
+type ftpConn struct {
+	*textproto.Conn
+	raw  net.Conn
+	data net.Conn
+}
+
+// cmd sends a command and reads its reply, which must have the class of
+// expect (e.g. 2 for any 2xx) or the exact code.
+func (c *ftpConn) cmd(expect int, format string, args ...any) (int, string, error) {
+	id, err := c.Cmd(format, args...)
+	if err != nil {
+		return 0, "", err
+	}
+	c.StartResponse(id)
+	defer c.EndResponse(id)
+	return c.ReadResponse(expect)
+}
+
+func (c *ftpConn) retrieve(ctx context.Context, dial func(context.Context, string, string) (net.Conn, error), u *url.URL, user, pass string) (io.ReadCloser, error) {
+	if _, _, err := c.ReadResponse(2); err != nil {
+		return nil, fmt.Errorf("robotstxt: ftp greeting: %w", err)
+	}
+	code, msg, err := c.cmd(0, "USER %s", user)
+	if err == nil && code == 331 {
+		code, msg, err = c.cmd(0, "PASS %s", pass)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("robotstxt: ftp login: %w", err)
+	}
+	if code != 230 {
+		return nil, fmt.Errorf("robotstxt: ftp login: %d %s", code, msg)
+	}
+	if _, _, err := c.cmd(2, "TYPE I"); err != nil {
+		return nil, fmt.Errorf("robotstxt: ftp TYPE: %w", err)
+	}
+	port, err := c.passivePort()
+	if err != nil {
+		return nil, err
+	}
+	host, _, _ := net.SplitHostPort(c.raw.RemoteAddr().String())
+	if c.data, err = dial(ctx, "tcp", net.JoinHostPort(host, port)); err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		c.data.SetDeadline(deadline)
+	}
+	code, msg, err = c.cmd(1, "RETR %s", u.Path)
+	if code == 550 {
+		return nil, fmt.Errorf("robotstxt: ftp %s: %s: %w", u.Path, msg, fs.ErrNotExist)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("robotstxt: ftp RETR: %w", err)
+	}
+	return c, nil
+}
+
+// passivePort enters passive mode and returns the data port. The data
+// connection goes to the control connection's peer, not to the address a
+// PASV reply advertises.
+func (c *ftpConn) passivePort() (string, error) {
+	if _, msg, err := c.cmd(229, "EPSV"); err == nil {
+		// "Entering Extended Passive Mode (|||6446|)"
+		if i := strings.Index(msg, "(|||"); i >= 0 {
+			if j := strings.Index(msg[i+4:], "|"); j >= 0 {
+				return msg[i+4 : i+4+j], nil
+			}
+		}
+	}
+	_, msg, err := c.cmd(227, "PASV")
+	if err != nil {
+		return "", fmt.Errorf("robotstxt: ftp PASV: %w", err)
+	}
+	// "Entering Passive Mode (h1,h2,h3,h4,p1,p2)"
+	i, j := strings.IndexByte(msg, '('), strings.IndexByte(msg, ')')
+	if i < 0 || j < i {
+		return "", fmt.Errorf("robotstxt: ftp PASV: bad reply %q", msg)
+	}
+	f := strings.Split(msg[i+1:j], ",")
+	if len(f) != 6 {
+		return "", fmt.Errorf("robotstxt: ftp PASV: bad reply %q", msg)
+	}
+	p1, err1 := strconv.Atoi(strings.TrimSpace(f[4]))
+	p2, err2 := strconv.Atoi(strings.TrimSpace(f[5]))
+	if err1 != nil || err2 != nil {
+		return "", fmt.Errorf("robotstxt: ftp PASV: bad reply %q", msg)
+	}
+	return strconv.Itoa(p1<<8 | p2), nil
+}
+
+// Read reads the file from the data connection.
+func (c *ftpConn) Read(p []byte) (int, error) {
+	return c.data.Read(p)
+}
+
+// Close closes the data connection, ends the session and closes the control
+// connection.
+func (c *ftpConn) Close() error {
+	if c.data != nil {
+		c.data.Close()
+		c.data = nil
+		c.ReadResponse(2)
+		c.cmd(2, "QUIT")
+	}
+	return c.Conn.Close()
+}
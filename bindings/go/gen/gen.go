@@ -0,0 +1,138 @@
+// Package gen generates synthetic robots.txt files from a seed, for
+// reproducible benchmarks and fuzz corpus seeding. The same Options always
+// produce the same output.
+//
+//	for i, body := range gen.Corpus(gen.Options{Seed: 1, Rules: 50, Wildcards: 0.2}, 100) {
+//		os.WriteFile(fmt.Sprintf("corpus/%03d", i), []byte(body), 0o644)
+//	}
+package gen
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+)
+
+// Options controls the generated files. Zero fields take the defaults noted.
+type Options struct {
+	Seed int64
+	// Groups is the number of user-agent groups (default 1). The first is
+	// the global "*" group.
+	Groups int
+	// Rules is the number of allow and disallow rules, spread over the
+	// groups (default 10).
+	Rules int
+	// Wildcards is the probability, 0 to 1, that a rule uses "*" or "$".
+	Wildcards float64
+	// Junk is the probability, 0 to 1, that a junk line (a comment, an
+	// unknown directive or garbage) follows each line.
+	Junk float64
+	// MinSize and MaxSize, when MaxSize is set, draw a target size in bytes
+	// log-uniformly from the range; files are grown with extra rules until
+	// they reach it.
+	MinSize int
+	MaxSize int
+}
+
+var (
+	agents   = []string{"Googlebot", "Bingbot", "GPTBot", "CCBot", "DuckDuckBot", "Applebot", "YandexBot", "Baiduspider"}
+	segments = []string{"admin", "api", "cart", "search", "private", "tmp", "user", "images", "static", "wp-admin", "cgi-bin", "docs", "v1", "login"}
+	exts     = []string{".php", ".pdf", ".json", ".html", ".js", ".gif"}
+	junk     = []string{"# generated", "Host: example.com", "Clean-param: ref /", "Noindex: /old/", "<html>", "::::", "Crawl-delay: soon", "\t  "}
+)
+
+// Generate returns one robots.txt file.
+func Generate(opts Options) string {
+	g := &generator{opts: opts, rnd: rand.New(rand.NewSource(opts.Seed))}
+	return g.file()
+}
+
+// Corpus returns n files, the i-th generated with seed opts.Seed+i.
+func Corpus(opts Options, n int) []string {
+	files := make([]string, n)
+	for i := range files {
+		o := opts
+		o.Seed += int64(i)
+		files[i] = Generate(o)
+	}
+	return files
+}
+
+type generator struct {
+	opts Options
+	rnd  *rand.Rand
+	b    strings.Builder
+}
+
+func (g *generator) file() string {
+	groups, rules := g.opts.Groups, g.opts.Rules
+	if groups <= 0 {
+		groups = 1
+	}
+	if rules <= 0 {
+		rules = 10
+	}
+	target := 0
+	if g.opts.MaxSize > 0 {
+		lo := math.Max(1, float64(g.opts.MinSize))
+		hi := math.Max(lo, float64(g.opts.MaxSize))
+		target = int(math.Exp(math.Log(lo) + g.rnd.Float64()*(math.Log(hi)-math.Log(lo))))
+	}
+
+	for i := 0; i < groups; i++ {
+		if i > 0 {
+			g.b.WriteByte('\n')
+		}
+		agent := "*"
+		if i > 0 {
+			agent = agents[(i-1)%len(agents)]
+		}
+		g.line("User-agent: " + agent)
+		n := rules / groups
+		if i < rules%groups {
+			n++
+		}
+		for j := 0; j < n; j++ {
+			g.rule()
+		}
+	}
+	for g.b.Len() < target {
+		g.rule()
+	}
+	if g.rnd.Intn(2) == 0 {
+		g.b.WriteString("\nSitemap: https://example.com/sitemap.xml\n")
+	}
+	return g.b.String()
+}
+
+// line writes s and, with probability Junk, a junk line after it.
+func (g *generator) line(s string) {
+	g.b.WriteString(s + "\n")
+	if g.opts.Junk > 0 && g.rnd.Float64() < g.opts.Junk {
+		g.b.WriteString(junk[g.rnd.Intn(len(junk))] + "\n")
+	}
+}
+
+func (g *generator) rule() {
+	key := "Disallow: "
+	if g.rnd.Intn(4) == 0 {
+		key = "Allow: "
+	}
+	var p strings.Builder
+	for n := 1 + g.rnd.Intn(3); n > 0; n-- {
+		p.WriteString("/" + segments[g.rnd.Intn(len(segments))])
+	}
+	if g.opts.Wildcards > 0 && g.rnd.Float64() < g.opts.Wildcards {
+		switch g.rnd.Intn(3) {
+		case 0:
+			p.WriteString("/*" + exts[g.rnd.Intn(len(exts))] + "$")
+		case 1:
+			p.WriteString("*?")
+		default:
+			p.WriteString("/*/")
+		}
+	} else {
+		p.WriteString("/")
+	}
+	g.line(key + p.String())
+}
@@ -0,0 +1,46 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+
+	robotstxt "github.com/nzrsky/robotstxt/bindings/go"
+)
+
+func TestGenerate(t *testing.T) {
+	opts := Options{Seed: 42, Groups: 3, Rules: 20, Wildcards: 0.5}
+	a, b := Generate(opts), Generate(opts)
+	if a != b {
+		t.Error("Generate is not deterministic")
+	}
+	opts.Seed++
+	if Generate(opts) == a {
+		t.Error("Expected a different file for a different seed")
+	}
+
+	p := robotstxt.Parse(a)
+	if p.Meta.Groups != 3 || p.Meta.Rules != 20 {
+		t.Errorf("Groups, Rules = %d, %d, want 3, 20", p.Meta.Groups, p.Meta.Rules)
+	}
+	if !strings.Contains(a, "*") {
+		t.Error("Expected wildcard rules")
+	}
+	if strings.Contains(Generate(Options{Seed: 1, Rules: 50}), "/*") {
+		t.Error("Expected no wildcards with Wildcards = 0")
+	}
+}
+
+func TestGenerateSizeAndJunk(t *testing.T) {
+	for _, body := range Corpus(Options{Seed: 7, MinSize: 2000, MaxSize: 8000, Junk: 1}, 10) {
+		if len(body) < 2000 {
+			t.Errorf("Size %d below MinSize", len(body))
+		}
+		p := robotstxt.Parse(body)
+		if p.Meta.Lines < 2*p.Meta.Rules {
+			t.Errorf("Expected a junk line after every line, got %d lines for %d rules", p.Meta.Lines, p.Meta.Rules)
+		}
+	}
+	if c := Corpus(Options{}, 3); len(c) != 3 || c[0] == c[1] {
+		t.Error("Corpus files should differ by seed")
+	}
+}
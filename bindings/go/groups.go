@@ -0,0 +1,167 @@
+package robotstxt
+
+import "strings"
+
+// GroupMode selects how repeated groups for the same user-agent combine.
+type GroupMode int
+
+const (
+	// GroupsMerge merges every group naming a user-agent into one, as RFC
+	// 9309 requires. This is the library's default behavior.
+	GroupsMerge GroupMode = iota
+	// GroupsLastWins uses only the last group naming a user-agent; earlier
+	// groups no longer apply to it.
+	GroupsLastWins
+)
+
+// SetGroupMode selects how repeated groups combine for IsAllowed,
+// IsAllowedMulti and Check. The default is GroupsMerge; Reset keeps the
+// mode and Pool.Put restores the default.
+func (m *Matcher) SetGroupMode(mode GroupMode) {
+	m.groupMode = mode
+}
+
+// prepare returns robotsTxt as the native matcher should see it under the
+// matcher's GroupMode and WildcardLimit.
+func (m *Matcher) prepare(robotsTxt string) string {
+	if m.wildcardLimit != (WildcardLimit{}) {
+		robotsTxt = limitWildcards(robotsTxt, m.wildcardLimit)
+	}
+	if m.groupMode == GroupsLastWins {
+		return lastGroupWins(robotsTxt)
+	}
+	return robotsTxt
+}
+
+// MergedGroup is a user-agent named in more than one group.
+type MergedGroup struct {
+	// Agent is the lowercase product token, or "*".
+	Agent string
+	// Lines are the user-agent lines naming Agent, one per group, in file
+	// order.
+	Lines []int
+}
+
+// MergedGroups reports the user-agents whose rules are spread over several
+// groups of robotsTxt and so merge under RFC 9309, a common source of
+// surprising verdicts.
+func MergedGroups(robotsTxt string) []MergedGroup {
+	var out []MergedGroup
+	for agent, lines := range agentGroups(Parse(robotsTxt)) {
+		if len(lines) > 1 {
+			out = append(out, MergedGroup{Agent: agent, Lines: lines})
+		}
+	}
+	// Report in file order of first appearance.
+	for i := 1; i < len(out); i++ {
+		for j := i; j > 0 && out[j].Lines[0] < out[j-1].Lines[0]; j-- {
+			out[j], out[j-1] = out[j-1], out[j]
+		}
+	}
+	return out
+}
+
+// AgentGroup is a user-agent group of a robots.txt file.
+type AgentGroup struct {
+	// Agents are the product tokens of the group's user-agent lines,
+	// lowercased, each once; "*" is the global agent.
+	Agents []string
+	// Lines are the user-agent lines naming Agents, in the same order.
+	Lines []int
+	// Directives are the group's other directives in file order. Sitemaps
+	// belong to no group and are left out.
+	Directives []Directive
+}
+
+// Groups splits p into user-agent groups the way the matcher does: only an
+// allow or disallow rule closes a run of user-agent lines, so other
+// directives between them do not start a new group. Directives before the
+// first user-agent line belong to no group and are dropped.
+func Groups(p *ParsedRobots) []AgentGroup {
+	var out []AgentGroup
+	sawRule := true
+	for _, d := range p.Directives {
+		switch d.Type {
+		case DirectiveUserAgent:
+			if sawRule {
+				out = append(out, AgentGroup{})
+				sawRule = false
+			}
+			g := &out[len(out)-1]
+			if agent := agentToken(d.Value); agent != "" && !containsString(g.Agents, agent) {
+				g.Agents = append(g.Agents, agent)
+				g.Lines = append(g.Lines, d.Line)
+			}
+			continue
+		case DirectiveAllow, DirectiveDisallow:
+			sawRule = true
+		case DirectiveSitemap:
+			continue
+		}
+		if len(out) > 0 {
+			g := &out[len(out)-1]
+			g.Directives = append(g.Directives, d)
+		}
+	}
+	return out
+}
+
+// agentGroups maps each user-agent token to the user-agent lines naming it,
+// keeping one line per group.
+func agentGroups(p *ParsedRobots) map[string][]int {
+	groups := make(map[string][]int)
+	for _, g := range Groups(p) {
+		for i, agent := range g.Agents {
+			groups[agent] = append(groups[agent], g.Lines[i])
+		}
+	}
+	return groups
+}
+
+// containsString reports whether list holds s.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// agentToken returns the product token of a user-agent line value the way
+// the matcher reads it: the leading run of [a-zA-Z_-], lowercased.
+func agentToken(value string) string {
+	if strings.HasPrefix(value, "*") {
+		return "*"
+	}
+	end := 0
+	for end < len(value) {
+		c := value[end]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '-') {
+			break
+		}
+		end++
+	}
+	return strings.ToLower(value[:end])
+}
+
+// supersededAgent replaces user-agent lines dropped by GroupsLastWins. It
+// keeps the group structure and line numbers intact and matches no crawler.
+const supersededAgent = "User-agent: robotstxt-superseded-group"
+
+// lastGroupWins rewrites robotsTxt so that each user-agent is only named in
+// its last group.
+func lastGroupWins(robotsTxt string) string {
+	var drop []int
+	for _, lines := range agentGroups(Parse(robotsTxt)) {
+		drop = append(drop, lines[:len(lines)-1]...)
+	}
+	if len(drop) == 0 {
+		return robotsTxt
+	}
+	lines := splitLines(robotsTxt)
+	for _, n := range drop {
+		lines[n-1] = supersededAgent
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,74 @@
+package robotstxt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupMode(t *testing.T) {
+	robotsTxt := "User-agent: FooBot\r\n" +
+		"User-agent: BarBot\r\n" +
+		"Disallow: /a\r\n" +
+		"\r\n" +
+		"User-agent: foobot/2.0\r\n" +
+		"Crawl-delay: 3\r\n" +
+		"Disallow: /b\r\n"
+	m := NewMatcher()
+	defer m.Free()
+
+	if m.IsAllowed(robotsTxt, "FooBot", "https://example.com/a") {
+		t.Error("Expected merged groups to disallow /a for FooBot")
+	}
+	m.SetGroupMode(GroupsLastWins)
+	if !m.IsAllowed(robotsTxt, "FooBot", "https://example.com/a") {
+		t.Error("Expected last group only to allow /a for FooBot")
+	}
+	if res := m.Check(robotsTxt, "FooBot", "https://example.com/b"); res.Allowed || res.MatchingLine != 7 {
+		t.Errorf("Expected /b disallowed by line 7, got %+v", res)
+	}
+	if m.IsAllowed(robotsTxt, "BarBot", "https://example.com/a") {
+		t.Error("Expected BarBot to keep its only group")
+	}
+	if m.IsAllowedMulti(robotsTxt, []string{"FooBot"}, "https://example.com/b") {
+		t.Error("Expected IsAllowedMulti to honor GroupsLastWins")
+	}
+}
+
+func TestMergedGroups(t *testing.T) {
+	robotsTxt := "User-agent: *\nDisallow: /x\n\n" +
+		"User-agent: FooBot\nUser-agent: FooBot/1.0\nCrawl-delay: 1\nUser-agent: BarBot\nAllow: /\n\n" +
+		"User-agent: *\nUser-agent: foobot\nDisallow: /y\n"
+	want := []MergedGroup{
+		{Agent: "*", Lines: []int{1, 10}},
+		{Agent: "foobot", Lines: []int{4, 11}},
+	}
+	if got := MergedGroups(robotsTxt); !reflect.DeepEqual(got, want) {
+		t.Errorf("MergedGroups = %+v, want %+v", got, want)
+	}
+	if got := MergedGroups("User-agent: *\nDisallow: /\n"); len(got) != 0 {
+		t.Errorf("Expected no merged groups, got %+v", got)
+	}
+}
+
+func TestGroups(t *testing.T) {
+	robotsTxt := "Sitemap: https://example.com/s.xml\n" +
+		"User-agent: FooBot/1.0\n" +
+		"Crawl-delay: 2\n" +
+		"User-agent: *\n" +
+		"User-agent: foobot\n" +
+		"Disallow: /a\n" +
+		"Sitemap: https://example.com/t.xml\n" +
+		"User-agent: BarBot\n" +
+		"Allow: /\n"
+	groups := Groups(Parse(robotsTxt))
+	if len(groups) != 2 {
+		t.Fatalf("Expected 2 groups, got %+v", groups)
+	}
+	if g := groups[0]; !reflect.DeepEqual(g.Agents, []string{"foobot", "*"}) || !reflect.DeepEqual(g.Lines, []int{2, 4}) ||
+		len(g.Directives) != 2 || g.Directives[0].Type != DirectiveCrawlDelay || g.Directives[1].Type != DirectiveDisallow {
+		t.Errorf("Unexpected first group %+v", g)
+	}
+	if g := groups[1]; !reflect.DeepEqual(g.Agents, []string{"barbot"}) || len(g.Directives) != 1 || g.Directives[0].Line != 9 {
+		t.Errorf("Unexpected second group %+v", g)
+	}
+}
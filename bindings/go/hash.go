@@ -0,0 +1,29 @@
+package robotstxt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Hash returns a stable content key for a robots.txt body: the lowercase hex
+// SHA-256 of content after converting CRLF and lone CR line endings to LF.
+// Files differing only in line endings hash equally, so caches, change
+// detection and deduplication agree on one key.
+func Hash(content []byte) string {
+	h := sha256.New()
+	for len(content) > 0 {
+		i := bytes.IndexByte(content, '\r')
+		if i < 0 {
+			h.Write(content)
+			break
+		}
+		h.Write(content[:i])
+		h.Write([]byte{'\n'})
+		content = content[i+1:]
+		if len(content) > 0 && content[0] == '\n' {
+			content = content[1:]
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
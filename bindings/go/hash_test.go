@@ -0,0 +1,25 @@
+package robotstxt
+
+import "testing"
+
+func TestHash(t *testing.T) {
+	lf := Hash([]byte("User-agent: *\nDisallow: /\n"))
+	// Stable across releases: SHA-256 of the LF-normalized content.
+	if want := "331ea9090db0c9f6f597bd9840fd5b171830f6e0b3ba1cb24dfa91f0c95aedc1"; lf != want {
+		t.Errorf("Hash = %s, want %s", lf, want)
+	}
+	for _, body := range []string{
+		"User-agent: *\r\nDisallow: /\r\n",
+		"User-agent: *\rDisallow: /\r",
+	} {
+		if got := Hash([]byte(body)); got != lf {
+			t.Errorf("Hash(%q) = %s, want %s", body, got, lf)
+		}
+	}
+	if Hash([]byte("User-agent: *\n\nDisallow: /\n")) == lf {
+		t.Error("Expected blank line to change the hash")
+	}
+	if Hash(nil) != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Error("Expected SHA-256 of empty input for nil")
+	}
+}
@@ -0,0 +1,176 @@
+package robotstxt
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BotCategory classifies a known crawler by purpose.
+type BotCategory string
+
+const (
+	// CategorySearch crawls for search engine indexes.
+	CategorySearch BotCategory = "search"
+	// CategoryAITraining collects content to train AI models.
+	CategoryAITraining BotCategory = "ai-training"
+	// CategoryAIInput fetches content on demand for AI answers and
+	// assistants.
+	CategoryAIInput BotCategory = "ai-input"
+	// CategoryArchiving preserves pages in web archives.
+	CategoryArchiving BotCategory = "archiving"
+	// CategorySEO crawls for SEO and backlink analysis.
+	CategorySEO BotCategory = "seo"
+	// CategorySocial fetches link previews for social networks.
+	CategorySocial BotCategory = "social"
+)
+
+// KnownAgent is a well-known crawler's robots.txt user-agent token.
+type KnownAgent struct {
+	Token    string      `json:"token"`
+	Category BotCategory `json:"category"`
+	Operator string      `json:"operator"`
+}
+
+//go:embed knownbots.json
+var knownBotsJSON []byte
+
+var knownBots struct {
+	once   sync.Once
+	agents []KnownAgent
+}
+
+func loadKnownBots() []KnownAgent {
+	knownBots.once.Do(func() {
+		if err := json.Unmarshal(knownBotsJSON, &knownBots.agents); err != nil {
+			panic("robotstxt: bad embedded knownbots.json: " + err.Error())
+		}
+	})
+	return knownBots.agents
+}
+
+// KnownAgents returns the known crawlers in category, or all of them if
+// category is empty. The result is a copy the caller may modify.
+func KnownAgents(category BotCategory) []KnownAgent {
+	var out []KnownAgent
+	for _, a := range loadKnownBots() {
+		if category == "" || a.Category == category {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// LookupAgent returns the known crawler with the given token, compared
+// case-insensitively as robots.txt matching does.
+func LookupAgent(token string) (KnownAgent, bool) {
+	for _, a := range loadKnownBots() {
+		if strings.EqualFold(a.Token, token) {
+			return a, true
+		}
+	}
+	return KnownAgent{}, false
+}
+
+// SuggestAgent returns the known crawler whose token is closest to an
+// unknown token, for warning about misspellings such as "GTPBot". It
+// returns false if token is itself known or nothing is within two edits.
+func SuggestAgent(token string) (KnownAgent, bool) {
+	if _, ok := LookupAgent(token); ok || token == "" || token == "*" {
+		return KnownAgent{}, false
+	}
+	lower := strings.ToLower(token)
+	best, bestDist := KnownAgent{}, 3
+	for _, a := range loadKnownBots() {
+		if d := editDistance(lower, strings.ToLower(a.Token)); d < bestDist {
+			best, bestDist = a, d
+		}
+	}
+	return best, bestDist < 3
+}
+
+// editDistance returns the Damerau-Levenshtein (optimal string alignment)
+// distance between a and b, so a transposition counts as one edit.
+func editDistance(a, b string) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] && prev2[j-2]+1 < cur[j] {
+				cur[j] = prev2[j-2] + 1
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// ClassifiedAgent is a user-agent named in a robots.txt file, labeled with
+// its known-crawler category.
+type ClassifiedAgent struct {
+	// Agent is the known crawler's token, or the lowercase product token
+	// (or "*") for agents that are not known.
+	Agent string
+	// Line is the first user-agent line naming the agent.
+	Line int
+	// Category and Operator are empty for unknown agents and "*".
+	Category BotCategory
+	Operator string
+	// Blocked reports whether the agent's groups disallow the whole site:
+	// a disallow of "/" (or "/*", "*") and no allow rules.
+	Blocked bool
+}
+
+// ClassifyGroups labels every user-agent of p's groups with its category
+// from the known-crawler database, in file order (by name within a line),
+// so reports can say how many AI crawlers a site blocks and which search
+// crawlers it allows. Agents named in several groups are listed once.
+func ClassifyGroups(p *ParsedRobots) []ClassifiedAgent {
+	var out []ClassifiedAgent
+	for token, lines := range agentGroups(p) {
+		c := ClassifiedAgent{Agent: token, Line: lines[0]}
+		if a, ok := LookupAgent(token); ok && token != "*" {
+			c.Agent, c.Category, c.Operator = a.Token, a.Category, a.Operator
+		}
+		rules := EffectiveRules(p, token)
+		for _, r := range rules {
+			if r.Allow {
+				c.Blocked = false
+				break
+			}
+			if r.Pattern == "/" || r.Pattern == "/*" || r.Pattern == "*" {
+				c.Blocked = true
+			}
+		}
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Line != out[j].Line {
+			return out[i].Line < out[j].Line
+		}
+		return out[i].Agent < out[j].Agent
+	})
+	return out
+}
@@ -0,0 +1,88 @@
+package robotstxt
+
+import "testing"
+
+func TestKnownAgents(t *testing.T) {
+	all := KnownAgents("")
+	if len(all) == 0 {
+		t.Fatal("Expected embedded known agents")
+	}
+	seen := map[string]bool{}
+	for _, a := range all {
+		if a.Token == "" || a.Category == "" || a.Operator == "" {
+			t.Errorf("Incomplete entry %+v", a)
+		}
+		if seen[a.Token] {
+			t.Errorf("Duplicate token %s", a.Token)
+		}
+		seen[a.Token] = true
+	}
+	for _, a := range KnownAgents(CategoryAITraining) {
+		if a.Category != CategoryAITraining {
+			t.Errorf("Unexpected category for %+v", a)
+		}
+	}
+	if a, ok := LookupAgent("gptbot"); !ok || a.Token != "GPTBot" || a.Category != CategoryAITraining {
+		t.Errorf("LookupAgent(gptbot) = %+v, %v", a, ok)
+	}
+	if _, ok := LookupAgent("NoSuchBot"); ok {
+		t.Error("Expected unknown token not to be found")
+	}
+}
+
+func TestSuggestAgent(t *testing.T) {
+	tests := []struct {
+		token, want string
+	}{
+		{"GTPBot", "GPTBot"},
+		{"Googelbot", "Googlebot"},
+		{"bingbott", "Bingbot"},
+		{"GPTBot", ""},
+		{"*", ""},
+		{"CompletelyDifferent", ""},
+	}
+	for _, tt := range tests {
+		a, ok := SuggestAgent(tt.token)
+		if ok != (tt.want != "") || a.Token != tt.want {
+			t.Errorf("SuggestAgent(%q) = %q, %v; want %q", tt.token, a.Token, ok, tt.want)
+		}
+	}
+}
+
+func TestClassifyGroups(t *testing.T) {
+	robotsTxt := `User-agent: GPTBot
+User-agent: ccbot
+Disallow: /
+
+User-agent: Googlebot
+Disallow: /private/
+
+User-agent: MyCrawler
+Disallow: /
+Allow: /public/
+
+User-agent: *
+Disallow: /*
+`
+	got := ClassifyGroups(Parse(robotsTxt))
+	want := []ClassifiedAgent{
+		{Agent: "GPTBot", Line: 1, Category: CategoryAITraining, Blocked: true},
+		{Agent: "CCBot", Line: 2, Category: CategoryAITraining, Blocked: true},
+		{Agent: "Googlebot", Line: 5, Category: CategorySearch},
+		{Agent: "mycrawler", Line: 8},
+		{Agent: "*", Line: 12, Blocked: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ClassifyGroups = %+v", got)
+	}
+	for i := range want {
+		g := got[i]
+		g.Operator = ""
+		if g != want[i] {
+			t.Errorf("ClassifyGroups[%d] = %+v, want %+v", i, g, want[i])
+		}
+	}
+	if got[0].Operator == "" {
+		t.Error("Operator not set for a known agent")
+	}
+}
@@ -0,0 +1,73 @@
+package robotstxt
+
+import (
+	"strings"
+	"text/tabwriter"
+)
+
+// VerdictMatrix holds the verdicts of several user-agents for several URLs
+// under one robots.txt.
+type VerdictMatrix struct {
+	Agents []string
+	URLs   []string
+	// Allowed[i][j] is the verdict for URLs[i] and Agents[j].
+	Allowed [][]bool
+}
+
+// Matrix checks every URL for every agent, for verifying that agents are
+// treated differently only where intended.
+func (m *Matcher) Matrix(robotsTxt string, agents, urls []string) VerdictMatrix {
+	v := VerdictMatrix{Agents: agents, URLs: urls, Allowed: make([][]bool, len(urls))}
+	for i, url := range urls {
+		v.Allowed[i] = make([]bool, len(agents))
+		for j, agent := range agents {
+			v.Allowed[i][j] = m.IsAllowed(robotsTxt, agent, url)
+		}
+	}
+	return v
+}
+
+// Differs reports whether the agents disagree on URLs[i].
+func (v VerdictMatrix) Differs(i int) bool {
+	for _, a := range v.Allowed[i] {
+		if a != v.Allowed[i][0] {
+			return true
+		}
+	}
+	return false
+}
+
+// Differences returns the indexes of the URLs the agents disagree on.
+func (v VerdictMatrix) Differences() []int {
+	var out []int
+	for i := range v.URLs {
+		if v.Differs(i) {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// String renders the matrix as an aligned text table, one row per URL and
+// one column per agent, with rows the agents disagree on marked "*".
+func (v VerdictMatrix) String() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	w.Write([]byte("\tURL\t" + strings.Join(v.Agents, "\t") + "\n"))
+	for i, url := range v.URLs {
+		row := " \t" + url
+		if v.Differs(i) {
+			row = "*\t" + url
+		}
+		for _, a := range v.Allowed[i] {
+			if a {
+				row += "\tallow"
+			} else {
+				row += "\tdisallow"
+			}
+		}
+		w.Write([]byte(row + "\n"))
+	}
+	w.Flush()
+	return b.String()
+}
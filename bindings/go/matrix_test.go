@@ -0,0 +1,32 @@
+package robotstxt
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatrix(t *testing.T) {
+	m := NewMatcher()
+	defer m.Free()
+	robotsTxt := "User-agent: Googlebot\nDisallow: /search\n\nUser-agent: Bingbot\nDisallow: /search\nDisallow: /beta/\n"
+	v := m.Matrix(robotsTxt, []string{"Googlebot", "Bingbot"}, []string{"/", "/search?q=x", "/beta/page"})
+	want := [][]bool{{true, true}, {false, false}, {true, false}}
+	if !reflect.DeepEqual(v.Allowed, want) {
+		t.Errorf("Allowed = %v, want %v", v.Allowed, want)
+	}
+	if d := v.Differences(); !reflect.DeepEqual(d, []int{2}) {
+		t.Errorf("Differences = %v, want [2]", d)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(v.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("String has %d lines, want 4:\n%s", len(lines), v)
+	}
+	if !strings.HasPrefix(lines[3], "*") || !strings.HasSuffix(lines[3], "allow      disallow") {
+		t.Errorf("differing row = %q", lines[3])
+	}
+	if strings.HasPrefix(lines[1], "*") {
+		t.Errorf("agreeing row marked: %q", lines[1])
+	}
+}
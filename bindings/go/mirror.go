@@ -0,0 +1,38 @@
+package robotstxt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// mirrorFetcher is the SchemeFetcher behind Fetcher.Mirror.
+type mirrorFetcher struct {
+	fsys fs.FS
+}
+
+// Open implements SchemeFetcher.
+func (m mirrorFetcher) Open(ctx context.Context, robotsURL string) (io.ReadCloser, error) {
+	name, err := mirrorPath(robotsURL)
+	if err != nil {
+		return nil, err
+	}
+	return m.fsys.Open(name)
+}
+
+// mirrorPath returns the path of robotsURL in a mirror tree: its lowercase
+// host[:port] followed by its path.
+func mirrorPath(robotsURL string) (string, error) {
+	u, err := parseAbsoluteURL(robotsURL)
+	if err != nil {
+		return "", err
+	}
+	name := strings.ToLower(u.Host) + path.Clean("/"+u.Path)
+	if !fs.ValidPath(name) {
+		return "", fmt.Errorf("robotstxt: no mirror path for %s", robotsURL)
+	}
+	return name, nil
+}
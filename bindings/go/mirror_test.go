@@ -0,0 +1,51 @@
+package robotstxt
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFetchMirror(t *testing.T) {
+	f := &Fetcher{
+		Mirror: fstest.MapFS{
+			"example.com/robots.txt":      {Data: []byte("User-agent: *\nDisallow: /private/\n")},
+			"example.com:8080/robots.txt": {Data: []byte("User-agent: *\nDisallow: /\n")},
+			"cdn.example/policy/robots":   {Data: []byte("User-agent: *\nAllow: /\n")},
+		},
+		Locations: map[string]string{"https://cdn.example": "/policy/robots"},
+	}
+	for _, tc := range []struct {
+		url    string
+		status int
+		body   string
+	}{
+		{"https://EXAMPLE.com/page", http.StatusOK, "User-agent: *\nDisallow: /private/\n"},
+		{"http://example.com:8080/", http.StatusOK, "User-agent: *\nDisallow: /\n"},
+		{"https://cdn.example/x", http.StatusOK, "User-agent: *\nAllow: /\n"},
+		{"ftp://example.com/", http.StatusOK, "User-agent: *\nDisallow: /private/\n"},
+		{"https://missing.example/", http.StatusNotFound, ""},
+	} {
+		res, err := f.Fetch(context.Background(), tc.url)
+		if err != nil {
+			t.Errorf("Fetch(%s): %v", tc.url, err)
+			continue
+		}
+		if res.StatusCode != tc.status || string(res.Body) != tc.body {
+			t.Errorf("Fetch(%s) = %d %q, want %d %q", tc.url, res.StatusCode, res.Body, tc.status, tc.body)
+		}
+	}
+}
+
+func TestMirrorPath(t *testing.T) {
+	for in, want := range map[string]string{
+		"https://example.com/robots.txt":      "example.com/robots.txt",
+		"http://example.com:8080/robots.txt":  "example.com:8080/robots.txt",
+		"https://example.com/a/../robots.txt": "example.com/robots.txt",
+	} {
+		if got, err := mirrorPath(in); err != nil || got != want {
+			t.Errorf("mirrorPath(%s) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package monitor
+
+import (
+	"sort"
+	"strings"
+
+	robotstxt "github.com/nzrsky/robotstxt/bindings/go"
+)
+
+// Directive is one normalized robots.txt directive.
+type Directive struct {
+	// UserAgent is the lowercased product token of an agent of the group
+	// the directive belongs to, as the matcher reads it, or "" for
+	// group-independent directives such as Sitemap.
+	UserAgent string `json:"user_agent"`
+	// Key is the lowercased directive name, e.g. "disallow".
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Change is a directive added to or removed from a robots.txt file.
+type Change struct {
+	Directive
+	Added bool `json:"added"`
+}
+
+// Diff returns the directives added and removed between two robots.txt
+// bodies. Ordering, comments, blank lines and key casing are ignored, and a
+// directive in a group with several agents counts once per agent.
+func Diff(previous, current []byte) []Change {
+	before := directives(previous)
+	after := directives(current)
+
+	var changes []Change
+	for d := range after {
+		if !before[d] {
+			changes = append(changes, Change{Directive: d, Added: true})
+		}
+	}
+	for d := range before {
+		if !after[d] {
+			changes = append(changes, Change{Directive: d})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if a.UserAgent != b.UserAgent {
+			return a.UserAgent < b.UserAgent
+		}
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		if a.Value != b.Value {
+			return a.Value < b.Value
+		}
+		return !a.Added && b.Added
+	})
+	return changes
+}
+
+// directives returns the directive set of body, grouped as the matcher
+// groups it.
+func directives(body []byte) map[Directive]bool {
+	set := make(map[Directive]bool)
+	p := robotstxt.Parse(string(body))
+	for _, d := range p.Directives {
+		if d.Type == robotstxt.DirectiveSitemap {
+			set[Directive{Key: directiveKey(d), Value: d.Value}] = true
+		}
+	}
+	for _, g := range robotstxt.Groups(p) {
+		for _, d := range g.Directives {
+			for _, agent := range g.Agents {
+				set[Directive{UserAgent: agent, Key: directiveKey(d), Value: d.Value}] = true
+			}
+		}
+	}
+	return set
+}
+
+// directiveKey returns the lowercased name of d.
+func directiveKey(d robotstxt.Directive) string {
+	if d.Type == robotstxt.DirectiveUnknown {
+		return strings.ToLower(d.Key)
+	}
+	return d.Type.String()
+}
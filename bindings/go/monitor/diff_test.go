@@ -0,0 +1,61 @@
+package monitor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	previous := []byte(`# old
+User-agent: *
+Disallow: /private/
+
+User-agent: GPTBot
+User-agent: CCBot
+Disallow: /
+Sitemap: https://example.com/old.xml
+`)
+	current := []byte(`User-agent: *
+disallow: /private/   # reordered and recased
+Disallow: /tmp/
+
+User-agent: GPTBot
+Disallow: /
+Sitemap: https://example.com/new.xml
+`)
+
+	want := []Change{
+		{Directive{"", "sitemap", "https://example.com/new.xml"}, true},
+		{Directive{"", "sitemap", "https://example.com/old.xml"}, false},
+		{Directive{"*", "disallow", "/tmp/"}, true},
+		{Directive{"ccbot", "disallow", "/"}, false},
+	}
+	if got := Diff(previous, current); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+}
+
+func TestDiffIdentical(t *testing.T) {
+	body := []byte("User-agent: *\nDisallow: /a\n")
+	if got := Diff(body, body); len(got) != 0 {
+		t.Errorf("Expected no changes, got %v", got)
+	}
+}
+
+func TestDiffGroups(t *testing.T) {
+	// Only rules close a group, so crawl-delay does not split a from b.
+	previous := []byte("User-agent: a\nCrawl-delay: 1\nUser-agent: b/2.0\nDisallow: /x\n")
+	current := []byte("User-agent: a\nUser-agent: b\nCrawl-delay: 1\nDisallow: /x\n")
+	if got := Diff(previous, current); len(got) != 0 {
+		t.Errorf("Expected no changes, got %v", got)
+	}
+
+	want := []Change{
+		{Directive{"a", "crawl-delay", "1"}, false},
+		{Directive{"a", "disallow", "/x"}, false},
+	}
+	current = []byte("User-agent: b\nCrawl-delay: 1\nDisallow: /x\n")
+	if got := Diff(previous, current); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() = %v, want %v", got, want)
+	}
+}
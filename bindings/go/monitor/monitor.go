@@ -0,0 +1,190 @@
+// Package monitor periodically refetches robots.txt for a set of origins and
+// reports changes.
+//
+// Example usage:
+//
+//	m := &monitor.Monitor{
+//		Origins:  []string{"https://example.com"},
+//		Interval: time.Hour,
+//		OnChange: func(e monitor.Event) { log.Printf("%s changed: %v", e.Origin, e.Changes) },
+//	}
+//	m.Run(ctx)
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	robotstxt "github.com/nzrsky/robotstxt/bindings/go"
+)
+
+// Snapshot is the observed state of one origin's robots.txt.
+type Snapshot struct {
+	Origin     string
+	StatusCode int
+	Body       []byte
+	// Hash is robotstxt.Hash of Body, so line-ending changes alone are not
+	// reported.
+	Hash      string
+	FetchedAt time.Time
+}
+
+// Event reports that an origin's robots.txt changed between two fetches.
+type Event struct {
+	Origin   string
+	Previous Snapshot
+	Current  Snapshot
+	// Changes lists the directives added and removed.
+	Changes []Change
+}
+
+// Store persists the last snapshot per origin.
+type Store interface {
+	Load(origin string) (Snapshot, bool, error)
+	Save(s Snapshot) error
+}
+
+// MemoryStore is an in-memory Store. The zero value is ready to use.
+type MemoryStore struct {
+	mu        sync.Mutex
+	snapshots map[string]Snapshot
+}
+
+// Load returns the last snapshot saved for origin.
+func (s *MemoryStore) Load(origin string) (Snapshot, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, ok := s.snapshots[origin]
+	return snap, ok, nil
+}
+
+// Save stores snap as the latest snapshot of its origin.
+func (s *MemoryStore) Save(snap Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.snapshots == nil {
+		s.snapshots = make(map[string]Snapshot)
+	}
+	s.snapshots[snap.Origin] = snap
+	return nil
+}
+
+// Monitor refetches robots.txt for Origins every Interval and calls OnChange
+// when the status code or body of an origin changes. The first fetch of an
+// origin only records a snapshot.
+type Monitor struct {
+	Origins []string
+	// Interval is the time between checks in Run. It must be positive.
+	Interval time.Duration
+
+	// Fetcher retrieves robots.txt; a Fetcher using Clock if nil.
+	Fetcher *robotstxt.Fetcher
+	// Clock schedules Run; robotstxt.SystemClock if nil.
+	Clock robotstxt.Clock
+	// Store keeps snapshots between checks; an in-memory store if nil.
+	Store Store
+
+	// OnChange is called for every detected change.
+	OnChange func(Event)
+	// Sinks receive every detected change after OnChange.
+	Sinks []EventSink
+	// OnError is called for fetch, store and sink errors, if not nil.
+	OnError func(origin string, err error)
+
+	once sync.Once
+}
+
+func (m *Monitor) init() {
+	m.once.Do(func() {
+		if m.Clock == nil {
+			m.Clock = robotstxt.SystemClock
+		}
+		if m.Fetcher == nil {
+			m.Fetcher = &robotstxt.Fetcher{Clock: m.Clock}
+		}
+		if m.Store == nil {
+			m.Store = &MemoryStore{}
+		}
+	})
+}
+
+// Run checks all origins immediately and then every Interval until ctx is
+// done. It returns an error without checking if Interval is not positive.
+func (m *Monitor) Run(ctx context.Context) error {
+	if m.Interval <= 0 {
+		return fmt.Errorf("monitor: Interval must be positive, have %v", m.Interval)
+	}
+	m.init()
+	next := m.Clock.Now()
+	for {
+		m.CheckAll(ctx)
+		// Keep a fixed cadence, skipping ticks missed by a slow round.
+		now := m.Clock.Now()
+		if next = next.Add(m.Interval); next.Before(now) {
+			next = now
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-m.Clock.After(next.Sub(now)):
+		}
+	}
+}
+
+// CheckAll checks every origin once.
+func (m *Monitor) CheckAll(ctx context.Context) {
+	for _, origin := range m.Origins {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := m.Check(ctx, origin); err != nil && m.OnError != nil {
+			m.OnError(origin, err)
+		}
+	}
+}
+
+// Check fetches robots.txt for origin, compares it with the stored snapshot
+// and reports a change through OnChange and Sinks. Sink errors are passed to
+// OnError rather than returned.
+func (m *Monitor) Check(ctx context.Context, origin string) error {
+	m.init()
+	res, err := m.Fetcher.Fetch(ctx, origin)
+	if err != nil {
+		return err
+	}
+	current := Snapshot{
+		Origin:     origin,
+		StatusCode: res.StatusCode,
+		Body:       res.Body,
+		Hash:       robotstxt.Hash(res.Body),
+		FetchedAt:  res.FetchedAt,
+	}
+
+	previous, ok, err := m.Store.Load(origin)
+	if err != nil {
+		return err
+	}
+	if err := m.Store.Save(current); err != nil {
+		return err
+	}
+	if !ok || (previous.Hash == current.Hash && previous.StatusCode == current.StatusCode) {
+		return nil
+	}
+	e := Event{
+		Origin:   origin,
+		Previous: previous,
+		Current:  current,
+		Changes:  Diff(previous.Body, current.Body),
+	}
+	if m.OnChange != nil {
+		m.OnChange(e)
+	}
+	for _, sink := range m.Sinks {
+		if err := sink.Send(ctx, e); err != nil && m.OnError != nil {
+			m.OnError(origin, err)
+		}
+	}
+	return nil
+}
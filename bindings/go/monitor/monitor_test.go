@@ -0,0 +1,130 @@
+package monitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nzrsky/robotstxt/bindings/go/robotstest"
+)
+
+type fakeOrigin struct {
+	mu     sync.Mutex
+	status int
+	body   string
+}
+
+func (f *fakeOrigin) set(status int, body string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.status, f.body = status, body
+}
+
+func (f *fakeOrigin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.WriteHeader(f.status)
+	w.Write([]byte(f.body))
+}
+
+func TestMonitorCheck(t *testing.T) {
+	origin := &fakeOrigin{status: http.StatusOK, body: "User-agent: *\nDisallow: /a\n"}
+	srv := httptest.NewServer(origin)
+	defer srv.Close()
+
+	var events []Event
+	m := &Monitor{
+		Origins:  []string{srv.URL},
+		OnChange: func(e Event) { events = append(events, e) },
+		OnError:  func(origin string, err error) { t.Errorf("%s: %v", origin, err) },
+	}
+	ctx := context.Background()
+
+	m.CheckAll(ctx)
+	m.CheckAll(ctx)
+	if len(events) != 0 {
+		t.Fatalf("Expected no events for first and unchanged fetches, got %d", len(events))
+	}
+
+	origin.set(http.StatusOK, "User-agent: *\nDisallow: /b\n")
+	m.CheckAll(ctx)
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event after body change, got %d", len(events))
+	}
+	e := events[0]
+	if e.Origin != srv.URL || e.Previous.Hash == e.Current.Hash || len(e.Changes) != 2 {
+		t.Errorf("Unexpected event %+v", e)
+	}
+
+	origin.set(http.StatusNotFound, "User-agent: *\nDisallow: /b\n")
+	m.CheckAll(ctx)
+	if len(events) != 2 || events[1].Current.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected an event for status change, got %d events", len(events))
+	}
+}
+
+func TestMonitorFetchError(t *testing.T) {
+	var errs int
+	m := &Monitor{
+		Origins: []string{"not a url"},
+		OnError: func(string, error) { errs++ },
+	}
+	m.CheckAll(context.Background())
+	if errs != 1 {
+		t.Errorf("Expected 1 error, got %d", errs)
+	}
+}
+
+func TestMonitorRunClock(t *testing.T) {
+	srv := robotstest.NewServer()
+	defer srv.Close()
+	srv.Robots("User-agent: *\nDisallow: /\n")
+	clock := robotstest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	m := &Monitor{Origins: []string{srv.URL}, Interval: time.Hour, Clock: clock}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- m.Run(ctx) }()
+	waitFor := func(hits int) {
+		t.Helper()
+		for deadline := time.Now().Add(5 * time.Second); clock.Waiters() == 0 || srv.Hits("/robots.txt") != hits; {
+			if time.Now().After(deadline) {
+				t.Fatalf("Timed out waiting for %d fetches, have %d", hits, srv.Hits("/robots.txt"))
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	waitFor(1)
+	clock.Advance(59 * time.Minute)
+	if n := srv.Hits("/robots.txt"); n != 1 {
+		t.Errorf("Fetches before the interval = %d, want 1", n)
+	}
+	clock.Advance(time.Minute)
+	waitFor(2)
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run = %v, want context.Canceled", err)
+	}
+
+	snap, _, _ := m.Store.Load(srv.URL)
+	if want := clock.Now(); !snap.FetchedAt.Equal(want) {
+		t.Errorf("Snapshot FetchedAt = %v, want %v", snap.FetchedAt, want)
+	}
+}
+
+func TestMonitorRunInterval(t *testing.T) {
+	srv := robotstest.NewServer()
+	defer srv.Close()
+	for _, interval := range []time.Duration{0, -time.Second} {
+		m := &Monitor{Origins: []string{srv.URL}, Interval: interval}
+		if err := m.Run(context.Background()); err == nil {
+			t.Errorf("Run with Interval %v returned nil", interval)
+		}
+	}
+	if n := srv.Hits("/robots.txt"); n != 0 {
+		t.Errorf("Fetches with a bad interval = %d, want 0", n)
+	}
+}
@@ -0,0 +1,98 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// EventSink receives change events, e.g. to notify an alerting system.
+type EventSink interface {
+	Send(ctx context.Context, e Event) error
+}
+
+// SinkFunc adapts a function to an EventSink.
+type SinkFunc func(ctx context.Context, e Event) error
+
+// Send calls f(ctx, e).
+func (f SinkFunc) Send(ctx context.Context, e Event) error {
+	return f(ctx, e)
+}
+
+// SignatureHeader carries the webhook HMAC signature as "sha256=<hex>".
+const SignatureHeader = "X-Robots-Signature"
+
+// Webhook is an EventSink that POSTs each event as JSON to URL. If Secret
+// is set, the body is signed with HMAC-SHA256 in SignatureHeader.
+type Webhook struct {
+	URL    string
+	Secret []byte
+	// Client is the HTTP client; http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// WebhookPayload is the JSON body sent by Webhook.
+type WebhookPayload struct {
+	Origin         string    `json:"origin"`
+	PreviousStatus int       `json:"previous_status"`
+	CurrentStatus  int       `json:"current_status"`
+	PreviousHash   string    `json:"previous_hash"`
+	CurrentHash    string    `json:"current_hash"`
+	FetchedAt      time.Time `json:"fetched_at"`
+	Changes        []Change  `json:"changes"`
+}
+
+// Send POSTs e to the webhook URL. Non-2xx responses are errors.
+func (w *Webhook) Send(ctx context.Context, e Event) error {
+	body, err := json.Marshal(WebhookPayload{
+		Origin:         e.Origin,
+		PreviousStatus: e.Previous.StatusCode,
+		CurrentStatus:  e.Current.StatusCode,
+		PreviousHash:   e.Previous.Hash,
+		CurrentHash:    e.Current.Hash,
+		FetchedAt:      e.Current.FetchedAt,
+		Changes:        e.Changes,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(w.Secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(w.Secret, body))
+	}
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("monitor: webhook %s returned %s", w.URL, resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the SignatureHeader value for body, for receivers verifying
+// webhook requests with hmac.Equal.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,74 @@
+package monitor
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhook(t *testing.T) {
+	secret := []byte("s3cret")
+	var got WebhookPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !hmac.Equal([]byte(r.Header.Get(SignatureHeader)), []byte(Sign(secret, body))) {
+			t.Error("Invalid signature")
+		}
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer srv.Close()
+
+	e := Event{
+		Origin:   "https://example.com",
+		Previous: Snapshot{StatusCode: 200, Hash: "a"},
+		Current:  Snapshot{StatusCode: 200, Hash: "b"},
+		Changes:  []Change{{Directive{"gptbot", "disallow", "/"}, true}},
+	}
+	w := &Webhook{URL: srv.URL, Secret: secret}
+	if err := w.Send(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+	if got.Origin != e.Origin || got.CurrentHash != "b" || len(got.Changes) != 1 || got.Changes[0].UserAgent != "gptbot" {
+		t.Errorf("Unexpected payload %+v", got)
+	}
+}
+
+func TestWebhookError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	w := &Webhook{URL: srv.URL}
+	if err := w.Send(context.Background(), Event{}); err == nil {
+		t.Error("Expected error for 500 response")
+	}
+}
+
+func TestMonitorSinks(t *testing.T) {
+	origin := &fakeOrigin{status: http.StatusOK, body: "User-agent: *\nAllow: /\n"}
+	srv := httptest.NewServer(origin)
+	defer srv.Close()
+
+	var sent []Event
+	m := &Monitor{
+		Origins: []string{srv.URL},
+		Sinks: []EventSink{SinkFunc(func(_ context.Context, e Event) error {
+			sent = append(sent, e)
+			return nil
+		})},
+	}
+	m.CheckAll(context.Background())
+	origin.set(http.StatusOK, "User-agent: MyBot\nDisallow: /\n")
+	m.CheckAll(context.Background())
+
+	if len(sent) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(sent))
+	}
+}
@@ -0,0 +1,167 @@
+package robotstxt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// OverrideAction is the policy an Override forces for an origin.
+type OverrideAction string
+
+const (
+	// OverrideAllow allows every URL on the origin.
+	OverrideAllow OverrideAction = "allow"
+	// OverrideDeny disallows every URL on the origin.
+	OverrideDeny OverrideAction = "deny"
+	// OverrideRobots evaluates URLs against the synthetic Robots body.
+	OverrideRobots OverrideAction = "robots"
+)
+
+// Override is an operator-forced policy for one origin.
+type Override struct {
+	Action OverrideAction `json:"action"`
+	// Robots is the robots.txt body used with OverrideRobots.
+	Robots string `json:"robots,omitempty"`
+}
+
+// Allowed reports whether url is allowed for userAgent under the override.
+// The matcher is only used for OverrideRobots.
+func (o Override) Allowed(m *Matcher, userAgent, url string) bool {
+	switch o.Action {
+	case OverrideAllow:
+		return true
+	case OverrideDeny:
+		return false
+	default:
+		return m.IsAllowed(o.Robots, userAgent, url)
+	}
+}
+
+// Overrides is a set of per-origin overrides loaded from a JSON file, the
+// only format supported, that maps origins to policies, for example:
+//
+//	{
+//	  "https://www.example.com": {"action": "allow"},
+//	  "http://blocked.example": {"action": "deny"},
+//	  "https://staging.example.com": {"action": "robots", "robots": "User-agent: *\nDisallow: /\n"}
+//	}
+//
+// Consult Lookup before fetching or matching robots.txt. Overrides is safe
+// for concurrent use and can be reloaded while in use.
+type Overrides struct {
+	path string
+
+	mu      sync.RWMutex
+	entries map[string]Override
+	modTime time.Time
+}
+
+// LoadOverrides reads the overrides file at path. The file must be JSON;
+// YAML is not supported.
+func LoadOverrides(path string) (*Overrides, error) {
+	o := &Overrides{path: path}
+	if _, err := o.Reload(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Lookup returns the override for the origin of rawURL, if any.
+func (o *Overrides) Lookup(rawURL string) (Override, bool) {
+	origin, err := Origin(rawURL)
+	if err != nil {
+		return Override{}, false
+	}
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	ov, ok := o.entries[origin]
+	return ov, ok
+}
+
+// Reload re-reads the file if it changed since the last load and reports
+// whether new overrides were installed. On error the previous overrides
+// stay in effect.
+func (o *Overrides) Reload() (bool, error) {
+	info, err := os.Stat(o.path)
+	if err != nil {
+		return false, err
+	}
+	o.mu.RLock()
+	unchanged := o.entries != nil && info.ModTime().Equal(o.modTime)
+	o.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	data, err := os.ReadFile(o.path)
+	if err != nil {
+		return false, err
+	}
+	entries, err := parseOverrides(data)
+	if err != nil {
+		perr := &ParseError{File: o.path, Err: err}
+		var syntax *json.SyntaxError
+		var typ *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntax):
+			// Offset is just past the offending byte.
+			perr.Line, perr.Col = position(data, syntax.Offset-1)
+		case errors.As(err, &typ):
+			perr.Line, perr.Col = position(data, typ.Offset)
+		}
+		return false, perr
+	}
+
+	o.mu.Lock()
+	o.entries = entries
+	o.modTime = info.ModTime()
+	o.mu.Unlock()
+	return true, nil
+}
+
+// Watch polls the file every interval and reloads it on change until ctx is
+// done, then returns ctx.Err(). Reload errors are passed to onError, if not
+// nil. It returns an error without polling if interval is not positive.
+func (o *Overrides) Watch(ctx context.Context, interval time.Duration, onError func(error)) error {
+	if interval <= 0 {
+		return fmt.Errorf("robotstxt: overrides watch interval must be positive, have %v", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if _, err := o.Reload(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+func parseOverrides(data []byte) (map[string]Override, error) {
+	var raw map[string]Override
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	entries := make(map[string]Override, len(raw))
+	for key, ov := range raw {
+		switch ov.Action {
+		case OverrideAllow, OverrideDeny, OverrideRobots:
+		default:
+			return nil, fmt.Errorf("origin %q: unknown action %q", key, ov.Action)
+		}
+		origin, err := Origin(key)
+		if err != nil {
+			return nil, err
+		}
+		entries[origin] = ov
+	}
+	return entries, nil
+}
@@ -0,0 +1,118 @@
+package robotstxt
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeOverrides(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.json")
+	writeOverrides(t, path, `{
+		"https://Owned.example.com:443": {"action": "allow"},
+		"http://blocked.example": {"action": "deny"},
+		"https://staging.example": {"action": "robots", "robots": "User-agent: *\nDisallow: /private/\n"}
+	}`, time.Unix(1000, 0))
+
+	o, err := LoadOverrides(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewMatcher()
+	defer m.Free()
+
+	tests := []struct {
+		url     string
+		found   bool
+		allowed bool
+	}{
+		{"https://owned.example.com/anything", true, true},
+		{"http://blocked.example/", true, false},
+		{"https://blocked.example/", false, false},
+		{"https://staging.example/private/x", true, false},
+		{"https://staging.example/public", true, true},
+		{"not a url", false, false},
+	}
+	for _, tt := range tests {
+		ov, ok := o.Lookup(tt.url)
+		if ok != tt.found {
+			t.Errorf("Lookup(%q) found = %v, want %v", tt.url, ok, tt.found)
+			continue
+		}
+		if ok && ov.Allowed(m, "Googlebot", tt.url) != tt.allowed {
+			t.Errorf("Allowed(%q) = %v, want %v", tt.url, !tt.allowed, tt.allowed)
+		}
+	}
+}
+
+func TestOverridesReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.json")
+	writeOverrides(t, path, `{"https://example.com": {"action": "deny"}}`, time.Unix(1000, 0))
+
+	o, err := LoadOverrides(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := o.Reload(); changed || err != nil {
+		t.Errorf("Reload of unchanged file = %v, %v", changed, err)
+	}
+
+	writeOverrides(t, path, `{"https://example.com": {"action": "bogus"}}`, time.Unix(2000, 0))
+	if _, err := o.Reload(); err == nil {
+		t.Error("Expected error for unknown action")
+	}
+	if ov, ok := o.Lookup("https://example.com/"); !ok || ov.Action != OverrideDeny {
+		t.Error("Expected previous overrides to stay in effect after a failed reload")
+	}
+
+	writeOverrides(t, path, `{"https://example.com": {"action": "allow"}}`, time.Unix(3000, 0))
+	if changed, err := o.Reload(); !changed || err != nil {
+		t.Fatalf("Reload of changed file = %v, %v", changed, err)
+	}
+	if ov, _ := o.Lookup("https://example.com/"); ov.Action != OverrideAllow {
+		t.Errorf("Expected allow after reload, got %q", ov.Action)
+	}
+}
+
+func TestOverridesWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.json")
+	writeOverrides(t, path, `{"https://example.com": {"action": "deny"}}`, time.Unix(1000, 0))
+	o, err := LoadOverrides(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Watch(context.Background(), 0, nil); err == nil {
+		t.Error("Expected error for a zero interval")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	// Reload errors are expected while the file is being rewritten.
+	go func() { done <- o.Watch(ctx, time.Millisecond, nil) }()
+	writeOverrides(t, path, `{"https://example.com": {"action": "allow"}}`, time.Unix(2000, 0))
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+		if ov, _ := o.Lookup("https://example.com/"); ov.Action == OverrideAllow {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for Watch to reload")
+		}
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Watch = %v, want context.Canceled", err)
+	}
+}
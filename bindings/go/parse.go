@@ -0,0 +1,167 @@
+package robotstxt
+
+/*
+#include "robots_c.h"
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// DirectiveType identifies a robots.txt directive.
+type DirectiveType int
+
+const (
+	// DirectiveUnknown is any directive the parser does not recognize; its
+	// name is in Directive.Key.
+	DirectiveUnknown DirectiveType = iota
+	DirectiveUserAgent
+	DirectiveAllow
+	DirectiveDisallow
+	DirectiveSitemap
+	DirectiveCrawlDelay
+	DirectiveRequestRate
+	DirectiveContentSignal
+)
+
+// String returns the canonical directive name, or "unknown".
+func (t DirectiveType) String() string {
+	switch t {
+	case DirectiveUserAgent:
+		return "user-agent"
+	case DirectiveAllow:
+		return "allow"
+	case DirectiveDisallow:
+		return "disallow"
+	case DirectiveSitemap:
+		return "sitemap"
+	case DirectiveCrawlDelay:
+		return "crawl-delay"
+	case DirectiveRequestRate:
+		return "request-rate"
+	case DirectiveContentSignal:
+		return "content-signal"
+	}
+	return "unknown"
+}
+
+// Directive is one directive line of a robots.txt file.
+type Directive struct {
+	Line int
+	Type DirectiveType
+	// Key is the directive name as written, set for DirectiveUnknown only.
+	Key string
+	// Value is the directive value. Allow and disallow patterns are
+	// percent-escaped the way the matcher sees them.
+	Value string
+}
+
+// ParsedRobots is the directive sequence of a robots.txt file, in file
+// order.
+type ParsedRobots struct {
+	Directives []Directive
+	Meta       ParseMetadata
+}
+
+// ParseMetadata describes a parsed document, for flagging pathological
+// files and building corpus statistics without a second pass.
+type ParseMetadata struct {
+	// Size is the document size in bytes.
+	Size int
+	// Lines is the number of lines, counted as the parser numbers them.
+	Lines int
+	// Rules is the number of allow and disallow directives.
+	Rules int
+	// Groups is the number of user-agent groups; consecutive user-agent
+	// lines form one group.
+	Groups int
+	// Duration is the time spent parsing.
+	Duration time.Duration
+	// LongLines is the number of lines that exceeded the parser's line
+	// length limit and had their tail ignored. It says nothing about the
+	// document as a whole; a body cut by Fetcher.MaxSize is reported by
+	// FetchResult.Truncated.
+	LongLines int
+}
+
+// Parse parses robotsTxt with the native parser, so directive recognition
+// (including accepted typos such as "disalow") matches the Matcher's.
+func Parse(robotsTxt string) *ParsedRobots {
+	start := time.Now()
+	cRobots := C.CString(robotsTxt)
+	defer C.free(unsafe.Pointer(cRobots))
+
+	parsed := C.robots_parse(cRobots, C.size_t(len(robotsTxt)))
+	if parsed == nil {
+		panic("robotstxt: out of memory")
+	}
+	defer C.robots_parsed_free(parsed)
+
+	var info C.robots_parse_info_t
+	C.robots_parsed_info(parsed, &info)
+	n := int(C.robots_parsed_count(parsed))
+	p := &ParsedRobots{
+		Directives: make([]Directive, 0, n),
+		Meta: ParseMetadata{
+			Size:      len(robotsTxt),
+			Lines:     int(info.lines),
+			LongLines: int(info.long_lines),
+		},
+	}
+	if n == 0 {
+		p.Meta.Duration = time.Since(start)
+		return p
+	}
+	var lines []string
+	prev := DirectiveUnknown
+	for _, d := range unsafe.Slice(C.robots_parsed_directives(parsed), n) {
+		dir := Directive{
+			Line:  int(d.line),
+			Type:  DirectiveType(d._type),
+			Key:   C.GoStringN(d.key, C.int(d.key_len)),
+			Value: C.GoStringN(d.value, C.int(d.value_len)),
+		}
+		switch dir.Type {
+		case DirectiveCrawlDelay, DirectiveRequestRate, DirectiveContentSignal:
+			// The native parser hands these over pre-parsed; keep the text.
+			if lines == nil {
+				lines = splitLines(robotsTxt)
+			}
+			if dir.Line <= len(lines) {
+				dir.Value = directiveValue(lines[dir.Line-1])
+			}
+		}
+		switch dir.Type {
+		case DirectiveAllow, DirectiveDisallow:
+			p.Meta.Rules++
+		case DirectiveUserAgent:
+			if prev != DirectiveUserAgent {
+				p.Meta.Groups++
+			}
+		}
+		prev = dir.Type
+		p.Directives = append(p.Directives, dir)
+	}
+	p.Meta.Duration = time.Since(start)
+	return p
+}
+
+// splitLines splits body into lines the way the parser numbers them: CR, LF
+// and CRLF each end a line.
+func splitLines(body string) []string {
+	var lines []string
+	for {
+		end := strings.IndexAny(body, "\r\n")
+		if end < 0 {
+			return append(lines, body)
+		}
+		lines = append(lines, body[:end])
+		if body[end] == '\r' && end+1 < len(body) && body[end+1] == '\n' {
+			end++
+		}
+		body = body[end+1:]
+	}
+}
@@ -0,0 +1,51 @@
+package robotstxt
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	robotsTxt := "User-agent: FooBot\r\n" +
+		"Disalow: /private/\r\n" +
+		"Allow: /café\r\n" +
+		"Crawl-delay: 1m # slow\r\n" +
+		"\r\n" +
+		"Sitemap: https://example.com/sitemap.xml\r\n" +
+		"X-Custom: hello\r\n"
+	p := Parse(robotsTxt)
+	want := []Directive{
+		{Line: 1, Type: DirectiveUserAgent, Value: "FooBot"},
+		{Line: 2, Type: DirectiveDisallow, Value: "/private/"},
+		{Line: 3, Type: DirectiveAllow, Value: "/caf%C3%A9"},
+		{Line: 4, Type: DirectiveCrawlDelay, Value: "1m"},
+		{Line: 6, Type: DirectiveSitemap, Value: "https://example.com/sitemap.xml"},
+		{Line: 7, Type: DirectiveUnknown, Key: "X-Custom", Value: "hello"},
+	}
+	if !reflect.DeepEqual(p.Directives, want) {
+		t.Errorf("Parse() =\n%+v\nwant\n%+v", p.Directives, want)
+	}
+	meta := p.Meta
+	if meta.Size != len(robotsTxt) || meta.Rules != 2 || meta.Groups != 1 || meta.LongLines != 0 || meta.Duration < 0 {
+		t.Errorf("Unexpected metadata %+v", meta)
+	}
+	if got := Parse("").Directives; len(got) != 0 {
+		t.Errorf("Expected no directives for empty input, got %+v", got)
+	}
+}
+
+func TestDirectiveTypeString(t *testing.T) {
+	if DirectiveDisallow.String() != "disallow" || DirectiveUnknown.String() != "unknown" {
+		t.Error("Unexpected DirectiveType names")
+	}
+}
+
+func TestParseMetadata(t *testing.T) {
+	robotsTxt := "User-agent: a\nUser-agent: b\nDisallow: /x\n\nUser-agent: c\nAllow: /\nDisallow: /" +
+		strings.Repeat("y", 20000) + "\n"
+	meta := Parse(robotsTxt).Meta
+	if meta.Lines < 7 || meta.Rules != 3 || meta.Groups != 2 || meta.LongLines != 1 {
+		t.Errorf("Unexpected metadata %+v", meta)
+	}
+}
@@ -0,0 +1,127 @@
+package policy
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	robotstxt "github.com/nzrsky/robotstxt/bindings/go"
+)
+
+// DomainList is a set of domains matched by suffix: "example.com" covers
+// example.com and every subdomain. Build it with ParseDomainList or
+// LoadDomainList; it is read-only afterwards and safe for concurrent use.
+type DomainList struct {
+	domains map[string]bool
+}
+
+// ParseDomainList reads one domain per line. Blank lines and "#" comments
+// are skipped, and a leading "*." or "." is ignored, so "*.example.com" and
+// "example.com" are the same entry.
+func ParseDomainList(r io.Reader) (*DomainList, error) {
+	l := &DomainList{domains: make(map[string]bool)}
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		d := strings.TrimLeft(strings.TrimSpace(line), "*.")
+		if d == "" {
+			continue
+		}
+		l.domains[hostOf("http://"+d)] = true
+	}
+	return l, sc.Err()
+}
+
+// LoadDomainList reads the domain list file at path.
+func LoadDomainList(path string) (*DomainList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseDomainList(f)
+}
+
+// Contains reports whether host or one of its parent domains is listed.
+func (l *DomainList) Contains(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for host != "" {
+		if l.domains[host] {
+			return true
+		}
+		i := strings.IndexByte(host, '.')
+		if i < 0 {
+			break
+		}
+		host = host[i+1:]
+	}
+	return false
+}
+
+// Blocklist returns a Layer named "blocklist" that denies requests to hosts
+// in l and abstains otherwise.
+func Blocklist(l *DomainList) Layer {
+	return Func("blocklist", func(req Request) Verdict {
+		if l.Contains(hostOf(req.URL)) {
+			return Deny
+		}
+		return Abstain
+	})
+}
+
+// Allowlist returns a Layer named "allowlist" that denies requests to hosts
+// not in l and abstains for listed hosts, leaving them to later layers such
+// as robots.txt.
+func Allowlist(l *DomainList) Layer {
+	return Func("allowlist", func(req Request) Verdict {
+		if l.Contains(hostOf(req.URL)) {
+			return Abstain
+		}
+		return Deny
+	})
+}
+
+// DNSBLTimeout bounds each DNSBL lookup, within any deadline of the
+// request's Context.
+const DNSBLTimeout = 2 * time.Second
+
+// DNSBL returns a Layer named "dnsbl" that denies requests to hosts listed
+// in a domain-based DNS blocklist: host is listed if host.zone resolves.
+// lookup is typically net.DefaultResolver.LookupHost. It gets a context
+// derived from Request.Context, so the caller's cancellation stops it.
+// Lookup failures, including timeouts and cancellation, abstain.
+func DNSBL(zone string, lookup func(ctx context.Context, host string) ([]string, error)) Layer {
+	zone = strings.Trim(zone, ".")
+	return Func("dnsbl", func(req Request) Verdict {
+		host := hostOf(req.URL)
+		if host == "" {
+			return Abstain
+		}
+		ctx, cancel := context.WithTimeout(req.context(), DNSBLTimeout)
+		defer cancel()
+		if addrs, err := lookup(ctx, host+"."+zone); err == nil && len(addrs) > 0 {
+			return Deny
+		}
+		return Abstain
+	})
+}
+
+// hostOf returns the lowercase ASCII host of rawURL, or "".
+func hostOf(rawURL string) string {
+	n, err := robotstxt.NormalizeURL(rawURL)
+	if err != nil {
+		return ""
+	}
+	u, err := url.Parse(n)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Hostname(), ".")
+}
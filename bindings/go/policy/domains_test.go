@@ -0,0 +1,88 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDomainList(t *testing.T) {
+	l, err := ParseDomainList(strings.NewReader("# blocked\nexample.com\n*.Bad.Example  # wildcard\n\nmünchen.example\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"example.com", true},
+		{"www.EXAMPLE.com.", true},
+		{"notexample.com", false},
+		{"bad.example", true},
+		{"x.y.bad.example", true},
+		{"xn--mnchen-3ya.example", true},
+		{"example.org", false},
+	}
+	for _, tt := range tests {
+		if got := l.Contains(tt.host); got != tt.want {
+			t.Errorf("Contains(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestDomainLayers(t *testing.T) {
+	l, _ := ParseDomainList(strings.NewReader("blocked.example\n"))
+	robots := "User-agent: *\nDisallow: /private/\n"
+	p := FirstDeny(Blocklist(l), RobotsThenOverrides(Robots(), fixed("overrides", Abstain)))
+
+	d := p.Decide(Request{UserAgent: "FooBot", URL: "https://www.blocked.example/", Robots: robots})
+	if d.Verdict != Deny || d.Layer != "blocklist" || len(d.Trace) != 1 {
+		t.Errorf("Expected deny by blocklist before robots, got %v by %q (%v)", d.Verdict, d.Layer, d.Trace)
+	}
+	d = p.Decide(Request{UserAgent: "FooBot", URL: "https://ok.example/", Robots: robots})
+	if d.Verdict != Allow || d.Layer != "robots" {
+		t.Errorf("Expected allow by robots, got %v by %q", d.Verdict, d.Layer)
+	}
+
+	a := Allowlist(l)
+	if v := a.Decide(Request{URL: "https://blocked.example/"}).Verdict; v != Abstain {
+		t.Errorf("Allowlist on listed host = %v, want abstain", v)
+	}
+	if v := a.Decide(Request{URL: "https://other.example/"}).Verdict; v != Deny {
+		t.Errorf("Allowlist on unlisted host = %v, want deny", v)
+	}
+}
+
+func TestDNSBL(t *testing.T) {
+	var queried []string
+	lookup := func(_ context.Context, host string) ([]string, error) {
+		queried = append(queried, host)
+		if host == "spam.example.dbl.test" {
+			return []string{"127.0.1.2"}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+	l := DNSBL("dbl.test.", lookup)
+	if d := l.Decide(Request{URL: "https://SPAM.example/x"}); d.Verdict != Deny || d.Layer != "dnsbl" {
+		t.Errorf("Expected deny by dnsbl, got %v by %q", d.Verdict, d.Layer)
+	}
+	if v := l.Decide(Request{URL: "https://ham.example/"}).Verdict; v != Abstain {
+		t.Errorf("Unlisted host = %v, want abstain", v)
+	}
+	if v := l.Decide(Request{URL: "not a url"}).Verdict; v != Abstain || len(queried) != 2 {
+		t.Errorf("Invalid URL = %v after %v, want abstain without lookup", v, queried)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l = DNSBL("dbl.test", func(ctx context.Context, host string) ([]string, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return []string{"127.0.1.2"}, nil
+	})
+	if v := l.Decide(Request{URL: "https://spam.example/", Context: ctx}).Verdict; v != Abstain {
+		t.Errorf("Cancelled lookup = %v, want abstain", v)
+	}
+}
@@ -0,0 +1,327 @@
+package policy
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	robotstxt "github.com/nzrsky/robotstxt/bindings/go"
+)
+
+// Expr returns a Layer named "expr" that evaluates the robots.txt verdict
+// and then decides with src, a boolean expression in a small CEL-like
+// language. true allows and false denies. For example, to always allow a
+// staging host:
+//
+//	verdict == "allow" || host == "staging.example.com"
+//
+// Variables are strings: url, host, path, agent, verdict ("allow" or
+// "deny") and rule (the matched allow/disallow pattern, or "" if none).
+// Expressions combine ==, !=, &&, ||, !, parentheses, string and boolean
+// literals, and the string methods startsWith, endsWith, contains and
+// matches (a regular expression, which must be a literal). Expr returns an
+// error if src does not compile or is not boolean. The Layer is safe for
+// concurrent use.
+func Expr(src string) (Layer, error) {
+	p := &exprParser{src: src}
+	p.next()
+	n, err := p.parseOr()
+	if err == nil && p.tok.kind != tokEOF {
+		err = p.errorf("unexpected %q", p.tok.text)
+	}
+	if err == nil && n.typ != typBool {
+		err = fmt.Errorf("policy: expression %q is not boolean", src)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var pool robotstxt.Pool
+	return Func("expr", func(req Request) Verdict {
+		m := pool.Get()
+		defer pool.Put(m)
+		env := exprEnv{"url": req.URL, "agent": req.UserAgent, "verdict": "deny"}
+		if m.IsAllowed(req.Robots, req.UserAgent, req.URL) {
+			env["verdict"] = "allow"
+		}
+		if u, err := url.Parse(req.URL); err == nil {
+			env["host"], env["path"] = u.Hostname(), u.EscapedPath()
+		}
+		if p.usesRule {
+			_, env["rule"], _, _ = m.MatchedRule()
+		}
+		if n.eval(env).(bool) {
+			return Allow
+		}
+		return Deny
+	}), nil
+}
+
+type exprEnv map[string]string
+
+var exprVars = map[string]bool{
+	"url": true, "host": true, "path": true, "agent": true, "verdict": true, "rule": true,
+}
+
+type exprType int
+
+const (
+	typString exprType = iota
+	typBool
+)
+
+// exprNode is a compiled, type-checked subexpression.
+type exprNode struct {
+	typ  exprType
+	eval func(exprEnv) any
+}
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokIdent
+	tokString
+	tokOp
+)
+
+type exprToken struct {
+	kind tokKind
+	text string
+	pos  int
+}
+
+type exprParser struct {
+	src      string
+	pos      int
+	tok      exprToken
+	usesRule bool
+}
+
+func (p *exprParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("policy: expression at offset %d: %s", p.tok.pos, fmt.Sprintf(format, args...))
+}
+
+// next advances to the next token. Malformed input surfaces as a token the
+// parser rejects.
+func (p *exprParser) next() {
+	for p.pos < len(p.src) && strings.IndexByte(" \t\r\n", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(p.src) {
+		p.tok = exprToken{kind: tokEOF, pos: start}
+		return
+	}
+	c := p.src[p.pos]
+	switch {
+	case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		for p.pos < len(p.src) && (p.src[p.pos] == '_' || p.src[p.pos] >= 'a' && p.src[p.pos] <= 'z' ||
+			p.src[p.pos] >= 'A' && p.src[p.pos] <= 'Z' || p.src[p.pos] >= '0' && p.src[p.pos] <= '9') {
+			p.pos++
+		}
+		p.tok = exprToken{kind: tokIdent, text: p.src[start:p.pos], pos: start}
+	case c == '"':
+		p.pos++
+		for p.pos < len(p.src) && p.src[p.pos] != '"' {
+			if p.src[p.pos] == '\\' {
+				p.pos++
+			}
+			p.pos++
+		}
+		p.pos++
+		if p.pos > len(p.src) {
+			p.pos = len(p.src)
+		}
+		p.tok = exprToken{kind: tokString, text: p.src[start:p.pos], pos: start}
+	default:
+		for _, op := range []string{"==", "!=", "&&", "||", "!", "(", ")", ".", ","} {
+			if strings.HasPrefix(p.src[p.pos:], op) {
+				p.pos += len(op)
+				p.tok = exprToken{kind: tokOp, text: op, pos: start}
+				return
+			}
+		}
+		p.pos++
+		p.tok = exprToken{kind: tokOp, text: p.src[start:p.pos], pos: start}
+	}
+}
+
+func (p *exprParser) accept(op string) bool {
+	if p.tok.kind == tokOp && p.tok.text == op {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *exprParser) parseOr() (*exprNode, error) {
+	return p.parseBinary("||", p.parseAnd, func(a, b func(exprEnv) any) func(exprEnv) any {
+		return func(e exprEnv) any { return a(e).(bool) || b(e).(bool) }
+	})
+}
+
+func (p *exprParser) parseAnd() (*exprNode, error) {
+	return p.parseBinary("&&", p.parseUnary, func(a, b func(exprEnv) any) func(exprEnv) any {
+		return func(e exprEnv) any { return a(e).(bool) && b(e).(bool) }
+	})
+}
+
+// parseBinary parses a left-associative chain of boolean op operands.
+func (p *exprParser) parseBinary(op string, operand func() (*exprNode, error),
+	combine func(a, b func(exprEnv) any) func(exprEnv) any) (*exprNode, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokOp && p.tok.text == op {
+		if left.typ != typBool {
+			return nil, p.errorf("%s needs boolean operands", op)
+		}
+		p.next()
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		if right.typ != typBool {
+			return nil, p.errorf("%s needs boolean operands", op)
+		}
+		left = &exprNode{typ: typBool, eval: combine(left.eval, right.eval)}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (*exprNode, error) {
+	if p.accept("!") {
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if n.typ != typBool {
+			return nil, p.errorf("! needs a boolean operand")
+		}
+		return &exprNode{typ: typBool, eval: func(e exprEnv) any { return !n.eval(e).(bool) }}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (*exprNode, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokOp || (p.tok.text != "==" && p.tok.text != "!=") {
+		return left, nil
+	}
+	op := p.tok.text
+	p.next()
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if left.typ != right.typ {
+		return nil, p.errorf("%s compares mismatched types", op)
+	}
+	eq := op == "=="
+	return &exprNode{typ: typBool, eval: func(e exprEnv) any {
+		return (left.eval(e) == right.eval(e)) == eq
+	}}, nil
+}
+
+func (p *exprParser) parsePrimary() (*exprNode, error) {
+	var n *exprNode
+	tok := p.tok
+	switch {
+	case p.accept("("):
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, p.errorf("missing )")
+		}
+		n = inner
+	case tok.kind == tokString:
+		s, err := strconv.Unquote(tok.text)
+		if err != nil {
+			return nil, p.errorf("invalid string %s", tok.text)
+		}
+		p.next()
+		n = &exprNode{typ: typString, eval: func(exprEnv) any { return s }}
+	case tok.kind == tokIdent && (tok.text == "true" || tok.text == "false"):
+		b := tok.text == "true"
+		p.next()
+		n = &exprNode{typ: typBool, eval: func(exprEnv) any { return b }}
+	case tok.kind == tokIdent && exprVars[tok.text]:
+		name := tok.text
+		p.usesRule = p.usesRule || name == "rule"
+		p.next()
+		n = &exprNode{typ: typString, eval: func(e exprEnv) any { return e[name] }}
+	case tok.kind == tokEOF:
+		return nil, p.errorf("unexpected end of expression")
+	default:
+		return nil, p.errorf("unexpected %q", tok.text)
+	}
+	for p.accept(".") {
+		var err error
+		if n, err = p.parseMethod(n); err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// parseMethod parses a string method call on recv, after the dot.
+func (p *exprParser) parseMethod(recv *exprNode) (*exprNode, error) {
+	name := p.tok.text
+	if p.tok.kind != tokIdent {
+		return nil, p.errorf("expected method name")
+	}
+	if recv.typ != typString {
+		return nil, p.errorf("%s needs a string receiver", name)
+	}
+	p.next()
+	if !p.accept("(") {
+		return nil, p.errorf("expected ( after %s", name)
+	}
+	argTok := p.tok
+	arg, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept(")") {
+		return nil, p.errorf("missing ) after %s argument", name)
+	}
+	if arg.typ != typString {
+		return nil, p.errorf("%s needs a string argument", name)
+	}
+
+	var fn func(s, a string) bool
+	switch name {
+	case "startsWith":
+		fn = strings.HasPrefix
+	case "endsWith":
+		fn = strings.HasSuffix
+	case "contains":
+		fn = strings.Contains
+	case "matches":
+		lit, err := strconv.Unquote(argTok.text)
+		if argTok.kind != tokString || err != nil {
+			return nil, p.errorf("matches needs a string literal")
+		}
+		re, err := regexp.Compile(lit)
+		if err != nil {
+			return nil, p.errorf("matches: %v", err)
+		}
+		return &exprNode{typ: typBool, eval: func(e exprEnv) any {
+			return re.MatchString(recv.eval(e).(string))
+		}}, nil
+	default:
+		return nil, p.errorf("unknown method %s", name)
+	}
+	return &exprNode{typ: typBool, eval: func(e exprEnv) any {
+		return fn(recv.eval(e).(string), arg.eval(e).(string))
+	}}, nil
+}
@@ -0,0 +1,56 @@
+package policy
+
+import "testing"
+
+func TestExpr(t *testing.T) {
+	robotsTxt := "User-agent: *\nDisallow: /private/\nDisallow: /tmp/\n"
+	tests := []struct {
+		expr string
+		url  string
+		want Verdict
+	}{
+		{`verdict == "allow"`, "https://example.com/", Allow},
+		{`verdict == "allow"`, "https://example.com/private/x", Deny},
+		{`verdict == "allow" || host == "staging.example.com"`, "https://staging.example.com/private/x", Allow},
+		{`verdict == "allow" || host == "staging.example.com"`, "https://www.example.com/private/x", Deny},
+		{`verdict == "allow" || rule == "/tmp/"`, "https://example.com/tmp/a", Allow},
+		{`verdict == "allow" || rule == "/tmp/"`, "https://example.com/private/a", Deny},
+		{`rule == ""`, "https://example.com/public", Allow},
+		{`verdict == "allow" && !path.startsWith("/api")`, "https://example.com/api/v1", Deny},
+		{`agent.contains("Foo") && (url.endsWith(".pdf") || path.matches("^/docs/"))`, "https://example.com/docs/a", Allow},
+		{`true`, "https://example.com/private/", Allow},
+	}
+	for _, tt := range tests {
+		l, err := Expr(tt.expr)
+		if err != nil {
+			t.Fatalf("Expr(%q): %v", tt.expr, err)
+		}
+		d := l.Decide(Request{UserAgent: "FooBot", URL: tt.url, Robots: robotsTxt})
+		if d.Verdict != tt.want || d.Layer != "expr" {
+			t.Errorf("Expr(%q) on %s = %v by %q, want %v", tt.expr, tt.url, d.Verdict, d.Layer, tt.want)
+		}
+	}
+}
+
+func TestExprErrors(t *testing.T) {
+	for _, src := range []string{
+		``,
+		`host`,
+		`host == `,
+		`host == true`,
+		`(verdict == "allow"`,
+		`verdict == "allow" &&`,
+		`host.startsWith(1)`,
+		`path.matches(host)`,
+		`path.matches("(")`,
+		`path.reverse("x")`,
+		`nosuchvar == "x"`,
+		`"unterminated`,
+		`!host`,
+		`host == "a" extra`,
+	} {
+		if _, err := Expr(src); err == nil {
+			t.Errorf("Expr(%q) = nil error, want error", src)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Package policy composes robots.txt verdicts with operator policies into a
+// single decision that records which layer decided.
+//
+// Example usage:
+//
+//	p := policy.RobotsThenOverrides(policy.Robots(), policy.Overrides(overrides))
+//	d := p.Decide(policy.Request{UserAgent: "Googlebot", URL: url, Robots: body})
+//	fmt.Printf("allowed=%v by %s\n", d.Allowed(), d.Layer)
+package policy
+
+import (
+	"context"
+
+	robotstxt "github.com/nzrsky/robotstxt/bindings/go"
+)
+
+// Verdict is the outcome of a single layer.
+type Verdict int
+
+const (
+	// Abstain means the layer has no opinion about the request.
+	Abstain Verdict = iota
+	// Allow means the layer allows the request.
+	Allow
+	// Deny means the layer denies the request.
+	Deny
+)
+
+// String returns "abstain", "allow" or "deny".
+func (v Verdict) String() string {
+	switch v {
+	case Allow:
+		return "allow"
+	case Deny:
+		return "deny"
+	default:
+		return "abstain"
+	}
+}
+
+// Request is the input to a decision.
+type Request struct {
+	UserAgent string
+	URL       string
+	// Robots is the robots.txt body for the URL's origin.
+	Robots string
+	// Context bounds layers that do I/O, such as DNSBL; nil means
+	// context.Background().
+	Context context.Context
+}
+
+// context returns req.Context, or context.Background() if it is nil.
+func (req Request) context() context.Context {
+	if req.Context == nil {
+		return context.Background()
+	}
+	return req.Context
+}
+
+// Step records the verdict of one consulted layer.
+type Step struct {
+	Layer   string
+	Verdict Verdict
+}
+
+// Decision is the outcome of a Layer with its provenance.
+type Decision struct {
+	Verdict Verdict
+	// Layer is the name of the layer that produced Verdict, or "" if every
+	// layer abstained.
+	Layer string
+	// Trace lists every layer consulted, in evaluation order.
+	Trace []Step
+}
+
+// Allowed reports whether the request may proceed. Abstain allows, like a
+// missing robots.txt.
+func (d Decision) Allowed() bool {
+	return d.Verdict != Deny
+}
+
+// Layer is one source of verdicts. Combinators are Layers as well.
+type Layer interface {
+	Name() string
+	Decide(req Request) Decision
+}
+
+type funcLayer struct {
+	name string
+	fn   func(Request) Verdict
+}
+
+// Func returns a Layer named name that decides with fn.
+func Func(name string, fn func(Request) Verdict) Layer {
+	return &funcLayer{name: name, fn: fn}
+}
+
+func (l *funcLayer) Name() string { return l.name }
+
+func (l *funcLayer) Decide(req Request) Decision {
+	return leaf(l.name, l.fn(req))
+}
+
+// Robots returns a Layer named "robots" that evaluates Request.Robots with
+// the matcher. It never abstains. It is safe for concurrent use.
+func Robots() Layer {
+	var pool robotstxt.Pool
+	return Func("robots", func(req Request) Verdict {
+		m := pool.Get()
+		defer pool.Put(m)
+		if m.IsAllowed(req.Robots, req.UserAgent, req.URL) {
+			return Allow
+		}
+		return Deny
+	})
+}
+
+// Overrides returns a Layer named "overrides" that applies the override for
+// the request's origin and abstains for origins without one.
+func Overrides(o *robotstxt.Overrides) Layer {
+	var pool robotstxt.Pool
+	return Func("overrides", func(req Request) Verdict {
+		ov, ok := o.Lookup(req.URL)
+		if !ok {
+			return Abstain
+		}
+		m := pool.Get()
+		defer pool.Put(m)
+		if ov.Allowed(m, req.UserAgent, req.URL) {
+			return Allow
+		}
+		return Deny
+	})
+}
+
+type combinator struct {
+	name   string
+	layers []Layer
+	// wins reports whether the decision of layers[i] ends evaluation.
+	wins func(i int, d Decision) bool
+}
+
+func (c *combinator) Name() string { return c.name }
+
+func (c *combinator) Decide(req Request) Decision {
+	var trace []Step
+	var final Decision
+	for i, l := range c.layers {
+		d := l.Decide(req)
+		trace = append(trace, d.Trace...)
+		if final.Verdict == Abstain {
+			final = d
+		}
+		if c.wins(i, d) {
+			final = d
+			break
+		}
+	}
+	final.Trace = trace
+	return final
+}
+
+// FirstDeny returns a Layer that denies if any layer denies, stopping at the
+// first denial. Otherwise the first layer that allows decides.
+func FirstDeny(layers ...Layer) Layer {
+	return &combinator{
+		name:   "first-deny",
+		layers: layers,
+		wins:   func(_ int, d Decision) bool { return d.Verdict == Deny },
+	}
+}
+
+// FirstMatch returns a Layer where the first layer that does not abstain
+// decides.
+func FirstMatch(layers ...Layer) Layer {
+	return &combinator{
+		name:   "first-match",
+		layers: layers,
+		wins:   func(_ int, d Decision) bool { return d.Verdict != Abstain },
+	}
+}
+
+// RobotsThenOverrides returns a Layer that evaluates robots and then
+// overrides; the overrides verdict replaces the robots verdict unless it
+// abstains. Both layers always appear in the trace.
+func RobotsThenOverrides(robots, overrides Layer) Layer {
+	return &combinator{
+		name:   "robots-then-overrides",
+		layers: []Layer{robots, overrides},
+		wins:   func(i int, d Decision) bool { return i == 1 && d.Verdict != Abstain },
+	}
+}
+
+func leaf(name string, v Verdict) Decision {
+	d := Decision{Verdict: v, Trace: []Step{{Layer: name, Verdict: v}}}
+	if v != Abstain {
+		d.Layer = name
+	}
+	return d
+}
@@ -0,0 +1,79 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	robotstxt "github.com/nzrsky/robotstxt/bindings/go"
+)
+
+func fixed(name string, v Verdict) Layer {
+	return Func(name, func(Request) Verdict { return v })
+}
+
+func TestFirstDeny(t *testing.T) {
+	p := FirstDeny(fixed("a", Abstain), fixed("b", Allow), fixed("c", Deny), fixed("d", Allow))
+	d := p.Decide(Request{})
+	if d.Verdict != Deny || d.Layer != "c" {
+		t.Errorf("Expected deny by c, got %v by %q", d.Verdict, d.Layer)
+	}
+	want := []Step{{"a", Abstain}, {"b", Allow}, {"c", Deny}}
+	if !reflect.DeepEqual(d.Trace, want) {
+		t.Errorf("Trace = %v, want %v", d.Trace, want)
+	}
+
+	d = FirstDeny(fixed("a", Abstain), fixed("b", Allow)).Decide(Request{})
+	if d.Verdict != Allow || d.Layer != "b" {
+		t.Errorf("Expected allow by b, got %v by %q", d.Verdict, d.Layer)
+	}
+}
+
+func TestFirstMatch(t *testing.T) {
+	d := FirstMatch(fixed("a", Abstain), fixed("b", Deny), fixed("c", Allow)).Decide(Request{})
+	if d.Verdict != Deny || d.Layer != "b" || len(d.Trace) != 2 {
+		t.Errorf("Expected deny by b after 2 steps, got %v by %q after %d", d.Verdict, d.Layer, len(d.Trace))
+	}
+
+	d = FirstMatch(fixed("a", Abstain)).Decide(Request{})
+	if d.Verdict != Abstain || d.Layer != "" || !d.Allowed() {
+		t.Errorf("Expected allowed abstain, got %v by %q", d.Verdict, d.Layer)
+	}
+}
+
+func TestRobotsThenOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "overrides.json")
+	if err := os.WriteFile(path, []byte(`{"https://owned.example": {"action": "allow"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	o, err := robotstxt.LoadOverrides(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := RobotsThenOverrides(Robots(), Overrides(o))
+	robots := "User-agent: *\nDisallow: /\n"
+
+	d := p.Decide(Request{UserAgent: "Googlebot", URL: "https://owned.example/page", Robots: robots})
+	if !d.Allowed() || d.Layer != "overrides" {
+		t.Errorf("Expected allow by overrides, got %v by %q", d.Verdict, d.Layer)
+	}
+
+	d = p.Decide(Request{UserAgent: "Googlebot", URL: "https://other.example/page", Robots: robots})
+	if d.Allowed() || d.Layer != "robots" {
+		t.Errorf("Expected deny by robots, got %v by %q", d.Verdict, d.Layer)
+	}
+	want := []Step{{"robots", Deny}, {"overrides", Abstain}}
+	if !reflect.DeepEqual(d.Trace, want) {
+		t.Errorf("Trace = %v, want %v", d.Trace, want)
+	}
+}
+
+func TestNestedCombinators(t *testing.T) {
+	p := FirstDeny(FirstMatch(fixed("a", Abstain), fixed("b", Allow)), fixed("c", Deny))
+	d := p.Decide(Request{})
+	if d.Verdict != Deny || d.Layer != "c" || len(d.Trace) != 3 {
+		t.Errorf("Expected deny by c after 3 steps, got %v by %q after %d", d.Verdict, d.Layer, len(d.Trace))
+	}
+}
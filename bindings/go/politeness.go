@@ -0,0 +1,225 @@
+package robotstxt
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// PolitenessProfile combines everything that paces requests to one origin
+// for one user-agent: the robots.txt crawl-delay, request-rate and visit
+// window, and operator bounds. It is plain data, so schedulers can persist
+// it in their frontier store.
+type PolitenessProfile struct {
+	Origin string `json:"origin"`
+	Agent  string `json:"agent"`
+	// CrawlDelay is the robots.txt crawl-delay, or 0 if none.
+	CrawlDelay time.Duration `json:"crawl_delay,omitempty"`
+	// RequestRate is the robots.txt request-rate, or nil. Its Window is the
+	// visit window.
+	RequestRate *RequestRate `json:"request_rate,omitempty"`
+	// MinDelay and MaxDelay are operator bounds on the delay between
+	// fetches. MinDelay applies even when robots.txt sets no delay; zero
+	// MaxDelay means no cap.
+	MinDelay time.Duration `json:"min_delay,omitempty"`
+	MaxDelay time.Duration `json:"max_delay,omitempty"`
+	// Clock supplies the current time to NextAllowedFetch and Wait;
+	// SystemClock if nil. It is not persisted.
+	Clock Clock `json:"-"`
+}
+
+// NewPolitenessProfile builds the profile for agent on origin from the
+// result of checking the origin's robots.txt. Operator bounds are left for
+// the caller to set.
+func NewPolitenessProfile(origin, agent string, r CheckResult) PolitenessProfile {
+	p := PolitenessProfile{Origin: origin, Agent: agent, RequestRate: r.RequestRate}
+	if r.CrawlDelay != nil && *r.CrawlDelay > 0 {
+		p.CrawlDelay = time.Duration(*r.CrawlDelay * float64(time.Second))
+	}
+	return p
+}
+
+// Delay returns the minimum time between two fetches: the larger of the
+// crawl-delay and the request-rate interval, clamped to the operator bounds.
+func (p PolitenessProfile) Delay() time.Duration {
+	d := p.CrawlDelay
+	if rr := p.RequestRate; rr != nil && rr.Requests > 0 {
+		if i := time.Duration(rr.Seconds) * time.Second / time.Duration(rr.Requests); i > d {
+			d = i
+		}
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d < p.MinDelay {
+		d = p.MinDelay
+	}
+	return d
+}
+
+// NextAllowedFetch returns the earliest time the origin may be fetched
+// again after a fetch at lastFetch: lastFetch plus Delay, moved to the start
+// of the next visit window if it falls outside it. A zero lastFetch means
+// the origin was never fetched, so only the visit window applies, starting
+// from now.
+func (p PolitenessProfile) NextAllowedFetch(lastFetch time.Time) time.Time {
+	next := lastFetch.Add(p.Delay())
+	if lastFetch.IsZero() {
+		next = clockOr(p.Clock).Now()
+	}
+	if p.RequestRate == nil || p.RequestRate.Window == nil {
+		return next
+	}
+	w := p.RequestRate.Window
+	if w.Contains(next) {
+		return next
+	}
+	u := next.UTC()
+	start := time.Date(u.Year(), u.Month(), u.Day(), 0, 0, 0, 0, time.UTC).Add(w.Start)
+	if start.Before(u) {
+		start = start.Add(24 * time.Hour)
+	}
+	return start.In(next.Location())
+}
+
+// Throughput is the most fetches a PolitenessProfile permits. Either field
+// is +Inf if nothing limits it.
+type Throughput struct {
+	// PerHour is the peak rate, within the visit window if there is one.
+	PerHour float64
+	// PerDay counts the fetches of a whole day, visit window included.
+	PerDay float64
+}
+
+// EstimateThroughput returns the maximum fetch rate the profile allows,
+// combining Delay with the visit window: a window of length w admits
+// ceil(w/Delay) fetches a day, the first at its start.
+func (p PolitenessProfile) EstimateThroughput() Throughput {
+	d, w := p.Delay(), p.window()
+	if w == 0 {
+		return Throughput{}
+	}
+	if d <= 0 {
+		return Throughput{PerHour: math.Inf(1), PerDay: math.Inf(1)}
+	}
+	t := Throughput{PerHour: float64(time.Hour) / float64(d), PerDay: float64(24*time.Hour) / float64(d)}
+	if w < 24*time.Hour {
+		t.PerDay = math.Ceil(float64(w) / float64(d))
+		if t.PerHour > t.PerDay {
+			t.PerHour = t.PerDay
+		}
+	}
+	return t
+}
+
+// CrawlDuration returns how long fetching n URLs takes at the profile's
+// maximum rate, from the first fetch at the start of the visit window to
+// the start of the last. It returns math.MaxInt64 if the visit window is
+// empty and n > 0, and saturates at math.MaxInt64 for crawls too long for
+// a time.Duration.
+func (p PolitenessProfile) CrawlDuration(n int) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	d, w := p.Delay(), p.window()
+	if w == 0 {
+		return math.MaxInt64
+	}
+	if n == 1 || d <= 0 {
+		return 0
+	}
+	if w == 24*time.Hour {
+		return mulDuration(int64(n-1), d)
+	}
+	perDay := 1 + int((w-1)/d)
+	days, rest := (n-1)/perDay, (n-1)%perDay
+	total, tail := mulDuration(int64(days), 24*time.Hour), time.Duration(rest)*d
+	if total > math.MaxInt64-tail {
+		return math.MaxInt64
+	}
+	return total + tail
+}
+
+// mulDuration returns n*d for n, d >= 0, saturating at math.MaxInt64.
+func mulDuration(n int64, d time.Duration) time.Duration {
+	if d > 0 && n > int64(math.MaxInt64/d) {
+		return math.MaxInt64
+	}
+	return time.Duration(n) * d
+}
+
+// window returns the length of the daily visit window, 24h if there is
+// none.
+func (p PolitenessProfile) window() time.Duration {
+	if p.RequestRate == nil || p.RequestRate.Window == nil {
+		return 24 * time.Hour
+	}
+	w := p.RequestRate.Window
+	if w.End < w.Start {
+		return w.End + 24*time.Hour - w.Start
+	}
+	return w.End - w.Start
+}
+
+// PolitenessStore shares per-origin fetch slots between crawler processes,
+// so that together they respect one delay per origin instead of each
+// applying it independently. Back it with an external store such as Redis
+// to coordinate across machines; MemoryPolitenessStore coordinates the
+// goroutines of one process.
+type PolitenessStore interface {
+	// Reserve claims the next fetch slot for origin and returns its time:
+	// now, or delay after the previously reserved slot if that is later.
+	// It must be atomic across all users of the store, for example a Redis
+	// Lua script over a per-origin key.
+	Reserve(ctx context.Context, origin string, delay time.Duration) (time.Time, error)
+}
+
+// MemoryPolitenessStore is an in-process PolitenessStore. The zero value is
+// ready to use and it is safe for concurrent use.
+type MemoryPolitenessStore struct {
+	// Clock supplies the current time; SystemClock if nil.
+	Clock Clock
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+// Reserve implements PolitenessStore.
+func (s *MemoryPolitenessStore) Reserve(ctx context.Context, origin string, delay time.Duration) (time.Time, error) {
+	if err := ctx.Err(); err != nil {
+		return time.Time{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slot := clockOr(s.Clock).Now()
+	if n, ok := s.next[origin]; ok && n.After(slot) {
+		slot = n
+	}
+	if s.next == nil {
+		s.next = make(map[string]time.Time)
+	}
+	s.next[origin] = slot.Add(delay)
+	return slot, nil
+}
+
+// Wait reserves a fetch slot for the profile's origin in store, spaced by
+// Delay, and blocks until it arrives or ctx is done. The visit window is not
+// applied; check NextAllowedFetch for that.
+func (p PolitenessProfile) Wait(ctx context.Context, store PolitenessStore) error {
+	slot, err := store.Reserve(ctx, p.Origin, p.Delay())
+	if err != nil {
+		return err
+	}
+	clock := clockOr(p.Clock)
+	d := slot.Sub(clock.Now())
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-clock.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
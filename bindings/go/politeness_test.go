@@ -0,0 +1,134 @@
+package robotstxt
+
+import (
+	"context"
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPolitenessProfile(t *testing.T) {
+	m := NewMatcher()
+	defer m.Free()
+	robotsTxt := "User-agent: *\nCrawl-delay: 2\nRequest-rate: 1/10 0600-1400\n"
+	p := NewPolitenessProfile("https://example.com", "FooBot", m.Check(robotsTxt, "FooBot", "https://example.com/"))
+	if p.CrawlDelay != 2*time.Second {
+		t.Errorf("CrawlDelay = %v, want 2s", p.CrawlDelay)
+	}
+	if d := p.Delay(); d != 10*time.Second {
+		t.Errorf("Delay = %v, want request-rate interval 10s", d)
+	}
+
+	last := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	if got, want := p.NextAllowedFetch(last), last.Add(10*time.Second); !got.Equal(want) {
+		t.Errorf("NextAllowedFetch in window = %v, want %v", got, want)
+	}
+	last = time.Date(2024, 5, 1, 13, 59, 55, 0, time.UTC)
+	if got, want := p.NextAllowedFetch(last), time.Date(2024, 5, 2, 6, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("NextAllowedFetch past window = %v, want %v", got, want)
+	}
+	last = time.Date(2024, 5, 1, 2, 0, 0, 0, time.UTC)
+	if got, want := p.NextAllowedFetch(last), time.Date(2024, 5, 1, 6, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("NextAllowedFetch before window = %v, want %v", got, want)
+	}
+
+	p.RequestRate = nil
+	p.MaxDelay = time.Second
+	if d := p.Delay(); d != time.Second {
+		t.Errorf("Delay with MaxDelay = %v, want 1s", d)
+	}
+	p.MinDelay = 5 * time.Second
+	if d := p.Delay(); d != 5*time.Second {
+		t.Errorf("Delay with MinDelay = %v, want 5s", d)
+	}
+	if got := (PolitenessProfile{}).NextAllowedFetch(time.Time{}); time.Since(got) > time.Minute {
+		t.Errorf("NextAllowedFetch of never-fetched origin = %v, want about now", got)
+	}
+}
+
+func TestMemoryPolitenessStore(t *testing.T) {
+	var s MemoryPolitenessStore
+	ctx := context.Background()
+	const delay = time.Hour
+	var wg sync.WaitGroup
+	slots := make([]time.Time, 4)
+	for i := range slots {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			slots[i], _ = s.Reserve(ctx, "https://example.com", delay)
+		}(i)
+	}
+	wg.Wait()
+	seen := make(map[int64]bool)
+	for _, slot := range slots {
+		seen[time.Until(slot).Round(time.Hour).Nanoseconds()/int64(time.Hour)] = true
+	}
+	for i := 0; i < len(slots); i++ {
+		if !seen[int64(i)] {
+			t.Errorf("Expected a slot %dh from now, got %v", i, slots)
+		}
+	}
+	if slot, _ := s.Reserve(ctx, "https://other.example", delay); time.Until(slot) > time.Second {
+		t.Errorf("Other origin slot = %v, want now", slot)
+	}
+
+	p := PolitenessProfile{Origin: "https://example.com", MinDelay: delay}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := p.Wait(ctx, &s); err != context.DeadlineExceeded {
+		t.Errorf("Wait = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestEstimateThroughput(t *testing.T) {
+	window := &TimeWindow{Start: 6 * time.Hour, End: 14 * time.Hour}
+	tests := []struct {
+		p       PolitenessProfile
+		perHour float64
+		perDay  float64
+		crawl   time.Duration // for 1000 URLs
+	}{
+		{PolitenessProfile{CrawlDelay: 10 * time.Second}, 360, 8640, 999 * 10 * time.Second},
+		{PolitenessProfile{RequestRate: &RequestRate{Requests: 1, Seconds: 60, Window: window}}, 60, 480, 2*24*time.Hour + 39*time.Minute},
+		{PolitenessProfile{CrawlDelay: 2 * time.Hour, RequestRate: &RequestRate{Requests: 1, Seconds: 1, Window: window}}, 0.5, 4, 249*24*time.Hour + 6*time.Hour},
+		{PolitenessProfile{RequestRate: &RequestRate{Requests: 1, Seconds: 3600, Window: &TimeWindow{Start: 22 * time.Hour, End: 2 * time.Hour}}}, 1, 4, 249*24*time.Hour + 3*time.Hour},
+		{PolitenessProfile{MinDelay: time.Second, MaxDelay: time.Second, CrawlDelay: time.Hour}, 3600, 86400, 999 * time.Second},
+	}
+	for i, tt := range tests {
+		got := tt.p.EstimateThroughput()
+		if got.PerHour != tt.perHour || got.PerDay != tt.perDay {
+			t.Errorf("%d: EstimateThroughput = %+v, want %v/h %v/day", i, got, tt.perHour, tt.perDay)
+		}
+		if d := tt.p.CrawlDuration(1000); d != tt.crawl {
+			t.Errorf("%d: CrawlDuration(1000) = %v, want %v", i, d, tt.crawl)
+		}
+	}
+
+	if got := (PolitenessProfile{}).EstimateThroughput(); !math.IsInf(got.PerHour, 1) || !math.IsInf(got.PerDay, 1) {
+		t.Errorf("unlimited EstimateThroughput = %+v, want +Inf", got)
+	}
+	if d := (PolitenessProfile{}).CrawlDuration(1000); d != 0 {
+		t.Errorf("unlimited CrawlDuration = %v, want 0", d)
+	}
+	empty := PolitenessProfile{RequestRate: &RequestRate{Requests: 1, Seconds: 1, Window: &TimeWindow{Start: time.Hour, End: time.Hour}}}
+	if got := empty.EstimateThroughput(); got != (Throughput{}) {
+		t.Errorf("empty window EstimateThroughput = %+v, want zero", got)
+	}
+	for _, n := range []int{1, 2} {
+		if d := empty.CrawlDuration(n); d != math.MaxInt64 {
+			t.Errorf("empty window CrawlDuration(%d) = %v, want max", n, d)
+		}
+	}
+	if d := empty.CrawlDuration(0); d != 0 {
+		t.Errorf("empty window CrawlDuration(0) = %v, want 0", d)
+	}
+	hourly := PolitenessProfile{CrawlDelay: time.Hour}
+	windowed := PolitenessProfile{CrawlDelay: time.Hour, RequestRate: &RequestRate{Requests: 1, Seconds: 1, Window: window}}
+	for _, p := range []PolitenessProfile{hourly, windowed} {
+		if d := p.CrawlDuration(math.MaxInt); d != math.MaxInt64 {
+			t.Errorf("CrawlDuration(MaxInt) = %v, want max", d)
+		}
+	}
+}
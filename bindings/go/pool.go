@@ -0,0 +1,37 @@
+package robotstxt
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Pool is a pool of reusable Matchers backed by sync.Pool, for services that
+// check many URLs and don't want to allocate a Matcher per request.
+//
+// Matchers returned by Get carry no state (matching line, crawl-delay,
+// request-rate, content-signal) and no options from previous uses. The zero
+// value is ready to use. A Pool is safe for concurrent use; the Matchers it
+// returns are not.
+type Pool struct {
+	pool sync.Pool
+}
+
+// Get returns a Matcher from the pool, creating one if the pool is empty.
+// Return it with Put when done instead of calling Free.
+func (p *Pool) Get() *Matcher {
+	if m, ok := p.pool.Get().(*Matcher); ok {
+		return m
+	}
+	return NewMatcher()
+}
+
+// Put resets the matcher, restores its options to their defaults and
+// returns it to the pool. Freed matchers are dropped.
+func (p *Pool) Put(m *Matcher) {
+	if m == nil || atomic.LoadPointer(&m.ptr) == nil {
+		return
+	}
+	m.Reset()
+	m.resetOptions()
+	p.pool.Put(m)
+}
@@ -0,0 +1,93 @@
+package robotstxt
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestPoolReset(t *testing.T) {
+	var p Pool
+
+	m := p.Get()
+	robotsTxt := "User-agent: *\nCrawl-delay: 5\nDisallow: /admin/\n"
+	if m.IsAllowed(robotsTxt, "Googlebot", "https://example.com/admin/") {
+		t.Error("Expected /admin/ to be disallowed")
+	}
+	if m.MatchingLine() == 0 || m.CrawlDelay() == nil {
+		t.Fatal("Expected matching line and crawl-delay to be set")
+	}
+	p.Put(m)
+
+	m = p.Get()
+	defer p.Put(m)
+	if line := m.MatchingLine(); line != 0 {
+		t.Errorf("Expected matching line 0 after Put, got %d", line)
+	}
+	if delay := m.CrawlDelay(); delay != nil {
+		t.Errorf("Expected no crawl-delay after Put, got %v", *delay)
+	}
+}
+
+func TestPoolResetOptions(t *testing.T) {
+	var p Pool
+	robotsTxt := "User-agent: *\nCrawl-delay: 5\n\nUser-agent: Googlebot\nDisallow: /private/\n\n" +
+		"User-agent: Googlebot\nAllow: /\nDisallow: /*?\nDisallow: /*x*y\n"
+
+	m := p.Get()
+	m.SetCrawlDelayMode(CrawlDelayMatchedGroup)
+	m.SetGroupMode(GroupsLastWins)
+	m.SetQueryMode(QueryExclude)
+	m.SetWildcardLimit(WildcardLimit{MaxWildcards: 1})
+	p.Put(m)
+
+	m = p.Get()
+	defer p.Put(m)
+	m.IsAllowed(robotsTxt, "Googlebot", "https://example.com/")
+	if delay := m.CrawlDelay(); delay == nil || *delay != 5 {
+		t.Errorf("Expected fallback crawl-delay 5 after Put, got %v", delay)
+	}
+	if m.IsAllowed(robotsTxt, "Googlebot", "https://example.com/private/") {
+		t.Error("Expected merged groups to disallow /private/ after Put")
+	}
+	if m.IsAllowed(robotsTxt, "Googlebot", "https://example.com/page?q=1") {
+		t.Error("Expected query string to be matched after Put")
+	}
+	if m.IsAllowed(robotsTxt, "Googlebot", "https://example.com/axby") {
+		t.Error("Expected no wildcard limit after Put")
+	}
+}
+
+func TestPoolPutFreed(t *testing.T) {
+	var p Pool
+
+	m := p.Get()
+	m.Free()
+	p.Put(m)
+
+	m = p.Get()
+	defer p.Put(m)
+	if !m.IsAllowed("User-agent: *\nAllow: /\n", "Googlebot", "https://example.com/") {
+		t.Error("Expected URL to be allowed")
+	}
+}
+
+func TestPoolConcurrent(t *testing.T) {
+	var p Pool
+	robotsTxt := "User-agent: *\nDisallow: /private/\n"
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m := p.Get()
+				if m.IsAllowed(robotsTxt, "Googlebot", "https://example.com/private/x") {
+					t.Error("Expected /private/x to be disallowed")
+				}
+				p.Put(m)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,29 @@
+package robotstxt
+
+// BlockCategories returns a Tenant that disallows the whole site for every
+// known crawler in categories (see KnownAgents) and leaves all other
+// crawlers, such as search engines, allowed. Blocking CategoryAITraining or
+// CategoryAIInput also declares the matching Content-Signal preference for
+// crawlers not on the list. Add site rules and sitemaps to the result as
+// needed, then Render it or pass it to TenantStore.Set.
+func BlockCategories(categories ...BotCategory) Tenant {
+	var t Tenant
+	for _, c := range categories {
+		for _, a := range KnownAgents(c) {
+			t.Rules = append(t.Rules, Rule{UserAgent: a.Token, Pattern: "/"})
+		}
+		switch c {
+		case CategoryAITraining:
+			if t.ContentSignal == nil {
+				t.ContentSignal = &ContentSignal{}
+			}
+			t.ContentSignal.AITrain = No
+		case CategoryAIInput:
+			if t.ContentSignal == nil {
+				t.ContentSignal = &ContentSignal{}
+			}
+			t.ContentSignal.AIInput = No
+		}
+	}
+	return t
+}
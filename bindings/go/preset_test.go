@@ -0,0 +1,51 @@
+package robotstxt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBlockCategories(t *testing.T) {
+	body, err := BlockCategories(CategoryAITraining).Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body, "User-agent: GPTBot\nDisallow: /\n") {
+		t.Errorf("Expected GPTBot group in:\n%s", body)
+	}
+	if !strings.HasSuffix(body, "User-agent: *\nContent-Signal: ai-train=no\n") {
+		t.Errorf("Expected trailing Content-Signal group in:\n%s", body)
+	}
+
+	m := NewMatcher()
+	defer m.Free()
+	for _, a := range KnownAgents(CategoryAITraining) {
+		if !IsValidUserAgent(a.Token) {
+			// Tokens such as AI2Bot are outside the matcher's [a-zA-Z_-]
+			// product-token alphabet; the group is still emitted.
+			continue
+		}
+		if m.IsAllowed(body, a.Token, "https://example.com/page") {
+			t.Errorf("Expected %s to be blocked", a.Token)
+		}
+	}
+	for _, ua := range []string{"Googlebot", "Bingbot", "ChatGPT-User"} {
+		if !m.IsAllowed(body, ua, "https://example.com/page") {
+			t.Errorf("Expected %s to be allowed", ua)
+		}
+	}
+	if ContentSignalSupported() {
+		m.IsAllowed(body, "SomeOtherBot", "https://example.com/")
+		if m.AllowsAITrain() || !m.AllowsSearch() {
+			t.Error("Expected ai-train=no for other crawlers")
+		}
+	}
+
+	both, _ := BlockCategories(CategoryAITraining, CategoryAIInput).Render()
+	if !strings.Contains(both, "Content-Signal: ai-input=no, ai-train=no\n") {
+		t.Errorf("Expected combined Content-Signal in:\n%s", both)
+	}
+	if body, _ := BlockCategories().Render(); body != "" {
+		t.Errorf("Expected empty robots.txt, got %q", body)
+	}
+}
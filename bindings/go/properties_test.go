@@ -0,0 +1,110 @@
+package robotstxt
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/nzrsky/robotstxt/bindings/go/gen"
+)
+
+// Property tests run with testing/quick; generated inputs come from the
+// types below.
+
+// literalPath is a path without wildcards or characters the matcher
+// escapes, e.g. "/a.b/c-d".
+type literalPath string
+
+func (literalPath) Generate(r *rand.Rand, size int) reflect.Value {
+	const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789/._-~"
+	b := []byte{'/'}
+	for n := r.Intn(size + 1); n > 0; n-- {
+		b = append(b, alphabet[r.Intn(len(alphabet))])
+	}
+	return reflect.ValueOf(literalPath(b))
+}
+
+// hostName mixes case, internationalized labels and punycode.
+type hostName string
+
+func (hostName) Generate(r *rand.Rand, size int) reflect.Value {
+	labels := []string{"www", "Example", "münchen", "xn--mnchen-3ya", "BÜCHER", "a-b", "com", "org", "日本"}
+	n := 1 + r.Intn(3)
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = labels[r.Intn(len(labels))]
+	}
+	return reflect.ValueOf(hostName(strings.Join(parts, ".")))
+}
+
+// robotsFile is a generated robots.txt with wildcards and junk lines.
+type robotsFile string
+
+func (robotsFile) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(robotsFile(gen.Generate(gen.Options{
+		Seed:      r.Int63(),
+		Groups:    1 + r.Intn(4),
+		Rules:     r.Intn(size + 1),
+		Wildcards: r.Float64(),
+		Junk:      r.Float64(),
+	})))
+}
+
+func TestPropertyAllowAllNeverDenies(t *testing.T) {
+	m := NewMatcher()
+	defer m.Free()
+	for _, body := range []string{"", "User-agent: *\nAllow: /\n", "User-agent: *\nDisallow:\n", "# nothing\n"} {
+		allowed := func(p literalPath) bool {
+			return m.IsAllowed(body, "FooBot", "https://example.com"+string(p))
+		}
+		if err := quick.Check(allowed, nil); err != nil {
+			t.Errorf("%q: %v", body, err)
+		}
+	}
+}
+
+func TestPropertyPrefixMonotonic(t *testing.T) {
+	m := NewMatcher()
+	defer m.Free()
+	// A literal rule matching a path matches every extension of it.
+	monotonic := func(prefix, rest literalPath) bool {
+		u := "https://example.com" + string(prefix) + strings.TrimPrefix(string(rest), "/")
+		disallow := "User-agent: *\nDisallow: " + string(prefix) + "\n"
+		allow := "User-agent: *\nDisallow: /\nAllow: " + string(prefix) + "\n"
+		return !m.IsAllowed(disallow, "FooBot", u) && m.IsAllowed(allow, "FooBot", u)
+	}
+	if err := quick.Check(monotonic, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPropertyNormalizeIdempotent(t *testing.T) {
+	idempotent := func(scheme bool, host hostName, p literalPath) bool {
+		u := "HTTP://" + string(host) + string(p)
+		if scheme {
+			u = "https://" + string(host) + ":8443" + string(p)
+		}
+		once, err := NormalizeURL(u)
+		if err != nil {
+			return true
+		}
+		twice, err := NormalizeURL(once)
+		return err == nil && twice == once
+	}
+	if err := quick.Check(idempotent, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPropertyFormatIdempotent(t *testing.T) {
+	idempotent := func(f robotsFile, align bool) bool {
+		opts := FormatOptions{Align: align, Indent: "  "}
+		once := Format(string(f), opts)
+		return Format(once, opts) == once
+	}
+	if err := quick.Check(idempotent, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,91 @@
+package robotstxt
+
+import (
+	"regexp"
+	"strings"
+)
+
+// QueryMode selects whether pattern matching sees a URL's query string.
+type QueryMode int
+
+const (
+	// QueryInclude matches patterns against the path and query, as RFC
+	// 9309 requires, so "Disallow: /*?sessionid=" blocks session URLs and
+	// "Disallow: /page$" does not block "/page?x=1". This is the library's
+	// default behavior.
+	QueryInclude QueryMode = iota
+	// QueryExclude strips the query string before matching: patterns only
+	// see the path.
+	QueryExclude
+)
+
+// SetQueryMode selects whether IsAllowed, IsAllowedMulti and Check match
+// patterns against the query string. The default is QueryInclude; Reset
+// keeps the mode and Pool.Put restores the default.
+func (m *Matcher) SetQueryMode(mode QueryMode) {
+	m.queryMode = mode
+}
+
+// QueryMode reports whether the matcher considers query strings.
+func (m *Matcher) QueryMode() QueryMode {
+	return m.queryMode
+}
+
+// target returns url as the native matcher should see it under the
+// matcher's QueryMode.
+func (m *Matcher) target(url string) string {
+	if m.queryMode != QueryExclude {
+		return url
+	}
+	q := strings.IndexByte(url, '?')
+	if q < 0 {
+		return url
+	}
+	if f := strings.IndexByte(url[q:], '#'); f >= 0 {
+		return url[:q] + url[q+f:]
+	}
+	return url[:q]
+}
+
+// MatchSpan is the part of a URL that the winning rule's pattern matched.
+// Patterns are anchored at the start of the path, so the match is always
+// Target[:End].
+type MatchSpan struct {
+	// Target is the path and query the pattern was matched against.
+	Target string
+	// End is where the shortest match of the pattern ends in Target.
+	End int
+	// Query reports whether the match reaches into the query string, that
+	// is, whether the rule only matched because of it.
+	Query bool
+}
+
+// MatchedSpan returns the part of url that the rule reported by
+// MatchedRule matched; url must be the URL of the most recent check. ok is
+// false if no rule matched.
+func (m *Matcher) MatchedSpan(url string) (span MatchSpan, ok bool) {
+	_, pattern, _, ok := m.MatchedRule()
+	if !ok {
+		return MatchSpan{}, false
+	}
+	re, end := patternRegex(pattern)
+	// Lazy wildcards find where the shortest match ends.
+	re = "^" + strings.ReplaceAll(re, ".*", ".*?")
+	if end {
+		re += "$"
+	}
+	rx, err := regexp.Compile(re)
+	if err != nil {
+		return MatchSpan{}, false
+	}
+	target := urlPathQuery(m.target(url))
+	loc := rx.FindStringIndex(target)
+	if loc == nil {
+		return MatchSpan{}, false
+	}
+	span = MatchSpan{Target: target, End: loc[1]}
+	if q := strings.IndexByte(target, '?'); q >= 0 && loc[1] > q {
+		span.Query = true
+	}
+	return span, true
+}
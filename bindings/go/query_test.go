@@ -0,0 +1,57 @@
+package robotstxt
+
+import (
+	"testing"
+)
+
+func TestQueryMode(t *testing.T) {
+	m := NewMatcher()
+	defer m.Free()
+	robotsTxt := "User-agent: *\nDisallow: /*?sessionid=\nDisallow: /page$\n"
+
+	if m.QueryMode() != QueryInclude {
+		t.Error("default QueryMode is not QueryInclude")
+	}
+	if m.IsAllowed(robotsTxt, "FooBot", "https://example.com/a?sessionid=1") {
+		t.Error("session URL allowed with QueryInclude")
+	}
+	if !m.IsAllowed(robotsTxt, "FooBot", "https://example.com/page?x=1") {
+		t.Error("/page$ matched a URL with a query under QueryInclude")
+	}
+
+	m.SetQueryMode(QueryExclude)
+	if !m.IsAllowed(robotsTxt, "FooBot", "https://example.com/a?sessionid=1#top") {
+		t.Error("session URL disallowed with QueryExclude")
+	}
+	if r := m.Check(robotsTxt, "FooBot", "https://example.com/page?x=1"); r.Allowed {
+		t.Error("/page$ did not match /page?x=1 under QueryExclude")
+	}
+	if m.IsAllowedMulti(robotsTxt, []string{"FooBot", "BarBot"}, "https://example.com/page?x=1") {
+		t.Error("IsAllowedMulti ignored QueryExclude")
+	}
+}
+
+func TestMatchedSpan(t *testing.T) {
+	m := NewMatcher()
+	defer m.Free()
+	robotsTxt := "User-agent: *\nDisallow: /*?sessionid=\nDisallow: /private\n"
+
+	url := "https://example.com/cart/view?sessionid=42&x=1"
+	m.IsAllowed(robotsTxt, "FooBot", url)
+	span, ok := m.MatchedSpan(url)
+	if !ok || span.Target != "/cart/view?sessionid=42&x=1" || span.Target[:span.End] != "/cart/view?sessionid=" || !span.Query {
+		t.Errorf("MatchedSpan = %+v, %v", span, ok)
+	}
+
+	url = "https://example.com/private/a?x=1"
+	m.IsAllowed(robotsTxt, "FooBot", url)
+	if span, ok := m.MatchedSpan(url); !ok || span.End != len("/private") || span.Query {
+		t.Errorf("path-only MatchedSpan = %+v, %v", span, ok)
+	}
+
+	url = "https://example.com/public"
+	m.IsAllowed(robotsTxt, "FooBot", url)
+	if _, ok := m.MatchedSpan(url); ok {
+		t.Error("MatchedSpan without a matched rule")
+	}
+}
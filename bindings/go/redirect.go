@@ -0,0 +1,59 @@
+package robotstxt
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// DefaultMaxRedirects is the default number of redirects Fetch follows.
+// RFC 9309 asks crawlers to follow at least five.
+const DefaultMaxRedirects = 10
+
+// Redirect errors returned by Fetch, wrapped in a *url.Error.
+var (
+	ErrRedirectLoop      = errors.New("robotstxt: redirect loop")
+	ErrRedirectDowngrade = errors.New("robotstxt: redirect from https to http")
+	ErrTooManyRedirects  = errors.New("robotstxt: too many redirects")
+)
+
+// RedirectPolicy controls how Fetch follows redirects. The zero value
+// follows up to DefaultMaxRedirects redirects, fails with ErrRedirectLoop
+// when a chain revisits a URL, and allows https to http redirects.
+type RedirectPolicy struct {
+	// Max is the number of redirects to follow; DefaultMaxRedirects if
+	// zero. A negative Max follows none: the 3xx response is the result.
+	Max int
+	// RefuseDowngrade fails a redirect from https to http with
+	// ErrRedirectDowngrade instead of following it.
+	RefuseDowngrade bool
+	// AllowRepeats turns off loop detection, for servers that bounce
+	// through the same URL to set a cookie. Max still bounds the chain.
+	AllowRepeats bool
+}
+
+// check is an http.Client CheckRedirect function applying p.
+func (p RedirectPolicy) check(req *http.Request, via []*http.Request) error {
+	max := p.Max
+	if max == 0 {
+		max = DefaultMaxRedirects
+	}
+	if max < 0 {
+		return http.ErrUseLastResponse
+	}
+	if len(via) > max {
+		return fmt.Errorf("%w: stopped after %d", ErrTooManyRedirects, max)
+	}
+	prev := via[len(via)-1].URL
+	if p.RefuseDowngrade && prev.Scheme == "https" && req.URL.Scheme == "http" {
+		return fmt.Errorf("%w: %s to %s", ErrRedirectDowngrade, prev, req.URL)
+	}
+	if !p.AllowRepeats {
+		for _, v := range via {
+			if v.URL.String() == req.URL.String() {
+				return fmt.Errorf("%w: %s", ErrRedirectLoop, req.URL)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package robotstxt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestFetchRedirects(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/robots.txt":
+			http.Redirect(w, r, "/bounce", http.StatusFound)
+		case r.URL.Path == "/bounce":
+			http.Redirect(w, r, "/robots.txt", http.StatusFound)
+		case strings.HasPrefix(r.URL.Path, "/chain/"):
+			n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/chain/"))
+			if n == 0 {
+				w.Write([]byte("User-agent: *\nDisallow: /\n"))
+				return
+			}
+			http.Redirect(w, r, "/chain/"+strconv.Itoa(n-1), http.StatusMovedPermanently)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	f := &Fetcher{}
+	if _, err := f.Fetch(ctx, srv.URL); !errors.Is(err, ErrRedirectLoop) {
+		t.Errorf("Fetch(loop) error = %v, want ErrRedirectLoop", err)
+	}
+	f.Redirects.AllowRepeats = true
+	if _, err := f.Fetch(ctx, srv.URL); !errors.Is(err, ErrTooManyRedirects) {
+		t.Errorf("Fetch(loop, AllowRepeats) error = %v, want ErrTooManyRedirects", err)
+	}
+
+	f = &Fetcher{Locations: map[string]string{srv.URL: "/chain/3"}, Redirects: RedirectPolicy{Max: 3}}
+	if res, err := f.Fetch(ctx, srv.URL); err != nil || res.StatusCode != http.StatusOK {
+		t.Errorf("Fetch(3 redirects, Max 3) = %+v, %v", res, err)
+	}
+	f.Redirects.Max = 2
+	if _, err := f.Fetch(ctx, srv.URL); !errors.Is(err, ErrTooManyRedirects) {
+		t.Errorf("Fetch(3 redirects, Max 2) error = %v, want ErrTooManyRedirects", err)
+	}
+	f.Redirects.Max = -1
+	if res, err := f.Fetch(ctx, srv.URL); err != nil || res.StatusCode != http.StatusMovedPermanently {
+		t.Errorf("Fetch(Max -1) = %+v, %v; want the 301 response", res, err)
+	}
+}
+
+func TestFetchRedirectDowngrade(t *testing.T) {
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("User-agent: *\nAllow: /\n"))
+	}))
+	defer plain.Close()
+	tls := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, plain.URL+"/robots.txt", http.StatusMovedPermanently)
+	}))
+	defer tls.Close()
+	ctx := context.Background()
+
+	f := &Fetcher{Client: tls.Client()}
+	if res, err := f.Fetch(ctx, tls.URL); err != nil || res.StatusCode != http.StatusOK {
+		t.Errorf("Fetch(downgrade allowed) = %+v, %v", res, err)
+	}
+	f.Redirects.RefuseDowngrade = true
+	if _, err := f.Fetch(ctx, tls.URL); !errors.Is(err, ErrRedirectDowngrade) {
+		t.Errorf("Fetch(downgrade refused) error = %v, want ErrRedirectDowngrade", err)
+	}
+}
@@ -0,0 +1,112 @@
+package robotstxt
+
+import (
+	"strings"
+)
+
+// RepairKind classifies a deviation the parser tolerates.
+type RepairKind string
+
+const (
+	// RepairMissingColon is a directive that uses whitespace instead of a
+	// colon as the separator, as in "Disallow /tmp".
+	RepairMissingColon RepairKind = "missing-colon"
+	// RepairEquals is a directive written with "=" as the separator, as in
+	// "Disallow= /tmp"; the parser only accepts it because of the space.
+	RepairEquals RepairKind = "equals"
+	// RepairTypo is a directive name recognized through an accepted typo or
+	// a prefix match, such as "disalow", "user agent" or "allowed".
+	RepairTypo RepairKind = "typo"
+	// RepairCase is a directive name in unconventional case, such as
+	// "DisAllow". Canonical, lowercase and "User-Agent" style names are fine.
+	RepairCase RepairKind = "case"
+	// RepairTab is a directive line containing a tab character.
+	RepairTab RepairKind = "tab"
+	// RepairBOM is a byte order mark after the start of the file. The parser
+	// only skips a leading one, so the line it is on is usually ignored.
+	RepairBOM RepairKind = "bom"
+)
+
+// Repair is one tolerated deviation on a line of a robots.txt file.
+type Repair struct {
+	Line int
+	Kind RepairKind
+	// Key is the directive name as written, if the line is a directive.
+	Key     string
+	Message string
+}
+
+// Repairs returns the deviations from well-formed syntax that the parser
+// silently tolerates in robotsTxt, in line order. A file without repairs
+// means the same to strict and lenient parsers; one with repairs may only
+// work because of the parser's leniency.
+func Repairs(robotsTxt string) []Repair {
+	directives := make(map[int]DirectiveType)
+	for _, d := range Parse(robotsTxt).Directives {
+		directives[d.Line] = d.Type
+	}
+
+	var out []Repair
+	for i, line := range splitLines(strings.TrimPrefix(robotsTxt, "\ufeff")) {
+		n := i + 1
+		if strings.Contains(line, "\ufeff") {
+			out = append(out, Repair{Line: n, Kind: RepairBOM, Message: "byte order mark after the start of the file"})
+		}
+		typ, ok := directives[n]
+		if !ok || typ == DirectiveUnknown {
+			continue
+		}
+		key, sep, value := splitDirective(line)
+		add := func(kind RepairKind, msg string) {
+			out = append(out, Repair{Line: n, Kind: kind, Key: key, Message: msg})
+		}
+		canonical := canonicalKeys[typ]
+		name := strings.TrimSuffix(key, "=")
+		switch {
+		case strings.HasSuffix(key, "=") || sep != ':' && strings.HasPrefix(value, "="):
+			add(RepairEquals, `"=" used as separator; use "`+canonical+`:"`)
+		case sep != ':':
+			add(RepairMissingColon, `missing ":" after "`+key+`"`)
+		}
+		switch {
+		case !strings.EqualFold(name, canonical):
+			add(RepairTypo, `"`+name+`" read as "`+canonical+`"`)
+		case !conventionalCase(name, canonical):
+			add(RepairCase, `"`+name+`" in unconventional case; use "`+canonical+`"`)
+		}
+		if strings.Contains(line, "\t") {
+			add(RepairTab, "tab character in directive line")
+		}
+	}
+	return out
+}
+
+// splitDirective splits a line into key, separator and value the way the
+// parser does: the first colon separates key and value, and whitespace may
+// stand in for a missing colon (reported as a separator of ' ').
+func splitDirective(line string) (key string, sep byte, value string) {
+	if i := strings.IndexByte(line, '#'); i >= 0 {
+		line = line[:i]
+	}
+	line = strings.TrimSpace(line)
+	if i := strings.IndexByte(line, ':'); i >= 0 {
+		return strings.TrimSpace(line[:i]), ':', strings.TrimSpace(line[i+1:])
+	}
+	if i := strings.IndexAny(line, " \t"); i >= 0 {
+		return line[:i], ' ', strings.TrimSpace(line[i:])
+	}
+	return line, 0, ""
+}
+
+// conventionalCase reports whether key is canonical, all lowercase, or
+// capitalizes every hyphenated word ("User-Agent").
+func conventionalCase(key, canonical string) bool {
+	if key == canonical || key == strings.ToLower(canonical) {
+		return true
+	}
+	words := strings.Split(strings.ToLower(canonical), "-")
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return key == strings.Join(words, "-")
+}
@@ -0,0 +1,47 @@
+package robotstxt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRepairs(t *testing.T) {
+	robots := "\ufeffUser-agent: *\n" +
+		"Disallow /missing\n" +
+		"Disallow= /equals\n" +
+		"disalow: /typo\n" +
+		"DisAllow: /case\n" +
+		"Allow:\t/tab\n" +
+		"\ufeffDisallow: /bom\n" +
+		"User-Agent: bot\n" +
+		"disallow: /fine # comment\n"
+	type kl struct {
+		Line int
+		Kind RepairKind
+	}
+	var got []kl
+	for _, r := range Repairs(robots) {
+		got = append(got, kl{r.Line, r.Kind})
+		if r.Message == "" {
+			t.Errorf("line %d %s: empty message", r.Line, r.Kind)
+		}
+	}
+	want := []kl{
+		{2, RepairMissingColon},
+		{3, RepairEquals},
+		{4, RepairTypo},
+		{5, RepairCase},
+		{6, RepairTab},
+		{7, RepairBOM},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Repairs = %v, want %v", got, want)
+	}
+}
+
+func TestRepairsWellFormed(t *testing.T) {
+	robots := "User-agent: *\nDisallow: /private/\nCrawl-delay: 5\n\nSitemap: https://example.com/s.xml\n"
+	if r := Repairs(robots); len(r) != 0 {
+		t.Errorf("Repairs(well-formed) = %v, want none", r)
+	}
+}
@@ -0,0 +1,110 @@
+// Package replay re-evaluates audited robots.txt decisions under the current
+// parser, to quantify behavioral drift when upgrading the library.
+//
+// The audit log holds one JSON Record per line; robots.txt bodies are
+// archived as files named by their robotstxt.Hash:
+//
+//	rep, err := replay.Run(logFile, os.DirFS("bodies"))
+//	for _, c := range rep.Changes {
+//		fmt.Printf("%s %s: %v -> %v\n", c.UserAgent, c.URL, c.Record.Allowed, c.Allowed)
+//	}
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+
+	robotstxt "github.com/nzrsky/robotstxt/bindings/go"
+)
+
+// Record is one past decision from the audit log.
+type Record struct {
+	Origin     string `json:"origin"`
+	RobotsHash string `json:"robots_hash"`
+	UserAgent  string `json:"user_agent"`
+	URL        string `json:"url"`
+	Allowed    bool   `json:"allowed"`
+}
+
+// Change is a record whose verdict differs under the current parser.
+type Change struct {
+	Record
+	// Line is the audit log line of the record.
+	Line int `json:"line"`
+	// Now is the current verdict and MatchingLine the robots.txt line that
+	// decided it.
+	Now          bool `json:"now"`
+	MatchingLine int  `json:"matching_line"`
+}
+
+// Report summarizes a replay.
+type Report struct {
+	// Total is the number of records read.
+	Total int `json:"total"`
+	// Missing counts records whose archived body is absent or does not match
+	// its hash; they are skipped.
+	Missing int      `json:"missing"`
+	Changes []Change `json:"changes"`
+}
+
+// Run replays every record of the audit log against the bodies archived in
+// bodies, each stored under its hash. It returns an error if the log cannot
+// be read or a line is not a valid record.
+func Run(log io.Reader, bodies fs.FS) (*Report, error) {
+	m := robotstxt.NewMatcher()
+	defer m.Free()
+
+	rep := &Report{}
+	cache := make(map[string]*string)
+	sc := bufio.NewScanner(log)
+	sc.Buffer(nil, 1<<20)
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return rep, fmt.Errorf("replay: line %d: %w", line, err)
+		}
+		rep.Total++
+
+		body, ok := cache[rec.RobotsHash]
+		if !ok {
+			body = loadBody(bodies, rec.RobotsHash)
+			cache[rec.RobotsHash] = body
+		}
+		if body == nil {
+			rep.Missing++
+			continue
+		}
+		if now := m.IsAllowed(*body, rec.UserAgent, rec.URL); now != rec.Allowed {
+			rep.Changes = append(rep.Changes, Change{
+				Record:       rec,
+				Line:         line,
+				Now:          now,
+				MatchingLine: m.MatchingLine(),
+			})
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return rep, fmt.Errorf("replay: %w", err)
+	}
+	return rep, nil
+}
+
+// loadBody returns the archived body for hash, or nil if it is missing or
+// does not hash to its name.
+func loadBody(bodies fs.FS, hash string) *string {
+	if !fs.ValidPath(hash) {
+		return nil
+	}
+	data, err := fs.ReadFile(bodies, hash)
+	if err != nil || robotstxt.Hash(data) != hash {
+		return nil
+	}
+	s := string(data)
+	return &s
+}
@@ -0,0 +1,44 @@
+package replay
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	robotstxt "github.com/nzrsky/robotstxt/bindings/go"
+)
+
+func TestRun(t *testing.T) {
+	body := "User-agent: *\nDisallow: /private/\n"
+	hash := robotstxt.Hash([]byte(body))
+	bodies := fstest.MapFS{
+		hash:           {Data: []byte(body)},
+		"0123456789ab": {Data: []byte("User-agent: *\nDisallow: /\n")},
+	}
+	log := strings.Join([]string{
+		`{"origin":"https://a.example","robots_hash":"` + hash + `","user_agent":"FooBot","url":"https://a.example/","allowed":true}`,
+		`{"origin":"https://a.example","robots_hash":"` + hash + `","user_agent":"FooBot","url":"https://a.example/private/x","allowed":true}`,
+		``,
+		`{"origin":"https://b.example","robots_hash":"0123456789ab","user_agent":"FooBot","url":"https://b.example/","allowed":false}`,
+		`{"origin":"https://c.example","robots_hash":"absent","user_agent":"FooBot","url":"https://c.example/","allowed":true}`,
+	}, "\n")
+
+	rep, err := Run(strings.NewReader(log), bodies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Total != 4 || rep.Missing != 2 {
+		t.Errorf("Total, Missing = %d, %d, want 4, 2", rep.Total, rep.Missing)
+	}
+	if len(rep.Changes) != 1 {
+		t.Fatalf("Changes = %+v, want one", rep.Changes)
+	}
+	c := rep.Changes[0]
+	if c.Line != 2 || c.Now || !c.Record.Allowed || c.MatchingLine != 2 {
+		t.Errorf("Change = %+v", c)
+	}
+
+	if _, err := Run(strings.NewReader("{not json"), bodies); err == nil {
+		t.Error("Expected error for invalid record")
+	}
+}
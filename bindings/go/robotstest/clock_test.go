@@ -0,0 +1,47 @@
+package robotstest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewClock(start)
+	if !c.Now().Equal(start) {
+		t.Errorf("Now = %v, want %v", c.Now(), start)
+	}
+	select {
+	case <-c.After(0):
+	default:
+		t.Error("After(0) did not fire at once")
+	}
+
+	short, long := c.After(time.Second), c.After(time.Minute)
+	if c.Waiters() != 2 {
+		t.Errorf("Waiters = %d, want 2", c.Waiters())
+	}
+	c.Advance(30 * time.Second)
+	select {
+	case now := <-short:
+		if !now.Equal(start.Add(30 * time.Second)) {
+			t.Errorf("After fired with %v", now)
+		}
+	default:
+		t.Error("After(1s) did not fire after 30s")
+	}
+	select {
+	case <-long:
+		t.Error("After(1m) fired after 30s")
+	default:
+	}
+	c.Advance(30 * time.Second)
+	select {
+	case <-long:
+	default:
+		t.Error("After(1m) did not fire after 1m")
+	}
+	if c.Waiters() != 0 {
+		t.Errorf("Waiters = %d, want 0", c.Waiters())
+	}
+}
@@ -0,0 +1,247 @@
+// Package robotstest provides a fake robots.txt server and assertion
+// helpers for testing crawler integrations without the network.
+//
+// Example usage:
+//
+//	srv := robotstest.NewServer()
+//	defer srv.Close()
+//	srv.Robots("User-agent: *\nDisallow: /private/\n")
+//	srv.Sequence("/other/robots.txt",
+//		robotstest.Response{Status: http.StatusServiceUnavailable},
+//		robotstest.Response{Body: "User-agent: *\nAllow: /\n"})
+//
+//	res, err := (&robotstxt.Fetcher{}).Fetch(ctx, srv.URL)
+//	robotstest.AssertDisallowed(t, string(res.Body), "MyBot", srv.URL+"/private/x")
+//	srv.AssertHits(t, "/robots.txt", 1)
+package robotstest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	robotstxt "github.com/nzrsky/robotstxt/bindings/go"
+)
+
+// Response is a canned response.
+type Response struct {
+	// Status is the status code; 200 if zero, or 302 with Redirect.
+	Status int
+	Body   string
+	Header http.Header
+	// Delay holds the response back, or until the client gives up.
+	Delay time.Duration
+	// Redirect, if set, is sent as the Location header.
+	Redirect string
+}
+
+// Request is a request the server received.
+type Request struct {
+	Method string
+	Path   string
+	Header http.Header
+	Time   time.Time
+}
+
+// route serves the responses for a path. Responses are served in order and
+// the last repeats; with every > 0, every every-th request gets failure.
+type route struct {
+	responses []Response
+	every     int
+	failure   Response
+	hits      int
+}
+
+// Server is a fake HTTP server with per-path canned responses. Paths
+// without a response get 404. It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	routes   map[string]*route
+	requests []Request
+}
+
+// NewServer starts a Server over HTTP. Call Close when done.
+func NewServer() *Server {
+	s := &Server{routes: make(map[string]*route)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// NewTLSServer starts a Server over HTTPS; use its Client, whose transport
+// trusts the server's certificate.
+func NewTLSServer() *Server {
+	s := &Server{routes: make(map[string]*route)}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Handle serves r for path, replacing any earlier responses.
+func (s *Server) Handle(path string, r Response) {
+	s.Sequence(path, r)
+}
+
+// Robots serves body as /robots.txt.
+func (s *Server) Robots(body string) {
+	s.Handle("/robots.txt", Response{Body: body})
+}
+
+// Sequence serves responses for path in order, one per request, repeating
+// the last, e.g. two 503s before a body for a server that recovers.
+func (s *Server) Sequence(path string, responses ...Response) {
+	if len(responses) == 0 {
+		responses = []Response{{Status: http.StatusNotFound}}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routes[path] = &route{responses: responses}
+}
+
+// Flaky makes every n-th request for path get failure instead of its
+// response, for a server that fails intermittently.
+func (s *Server) Flaky(path string, n int, failure Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rt, ok := s.routes[path]
+	if !ok {
+		rt = &route{responses: []Response{{Status: http.StatusNotFound}}}
+		s.routes[path] = rt
+	}
+	rt.every, rt.failure = n, failure
+}
+
+// RedirectChain redirects from through hops intermediate paths
+// (from/1, from/2, ...) to to, with 302s.
+func (s *Server) RedirectChain(from, to string, hops int) {
+	next := to
+	for i := hops; i >= 1; i-- {
+		hop := from + "/" + strconv.Itoa(i)
+		s.Handle(hop, Response{Redirect: next})
+		next = hop
+	}
+	s.Handle(from, Response{Redirect: next})
+}
+
+// Requests returns the requests received so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Hits returns the number of requests received for path.
+func (s *Server) Hits(path string) int {
+	n := 0
+	for _, r := range s.Requests() {
+		if r.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+// Reset forgets the recorded requests and restarts sequences.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+	for _, rt := range s.routes {
+		rt.hits = 0
+	}
+}
+
+func (s *Server) serve(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Header: req.Header.Clone(),
+		Time:   time.Now(),
+	})
+	resp := Response{Status: http.StatusNotFound}
+	if rt, ok := s.routes[req.URL.Path]; ok {
+		rt.hits++
+		switch {
+		case rt.every > 0 && rt.hits%rt.every == 0:
+			resp = rt.failure
+		case rt.hits <= len(rt.responses):
+			resp = rt.responses[rt.hits-1]
+		default:
+			resp = rt.responses[len(rt.responses)-1]
+		}
+	}
+	s.mu.Unlock()
+
+	if resp.Delay > 0 {
+		select {
+		case <-time.After(resp.Delay):
+		case <-req.Context().Done():
+			return
+		}
+	}
+	for k, v := range resp.Header {
+		w.Header()[k] = v
+	}
+	status := resp.Status
+	if resp.Redirect != "" {
+		w.Header().Set("Location", resp.Redirect)
+		if status == 0 {
+			status = http.StatusFound
+		}
+	}
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	w.Write([]byte(resp.Body))
+}
+
+// AssertHits fails t unless path was requested exactly n times.
+func (s *Server) AssertHits(t testing.TB, path string, n int) {
+	t.Helper()
+	if got := s.Hits(path); got != n {
+		t.Errorf("robotstest: %s requested %d times, want %d", path, got, n)
+	}
+}
+
+// AssertUserAgent fails t unless every request so far sent a User-Agent
+// containing product, and there was at least one.
+func (s *Server) AssertUserAgent(t testing.TB, product string) {
+	t.Helper()
+	reqs := s.Requests()
+	if len(reqs) == 0 {
+		t.Errorf("robotstest: no requests, want User-Agent %q", product)
+	}
+	for _, r := range reqs {
+		if ua := r.Header.Get("User-Agent"); !strings.Contains(ua, product) {
+			t.Errorf("robotstest: %s sent User-Agent %q, want %q", r.Path, ua, product)
+		}
+	}
+}
+
+// AssertAllowed fails t unless robotsTxt allows agent to fetch url.
+func AssertAllowed(t testing.TB, robotsTxt, agent, url string) {
+	t.Helper()
+	if !allowed(robotsTxt, agent, url) {
+		t.Errorf("robotstest: %s disallowed for %s, want allowed", url, agent)
+	}
+}
+
+// AssertDisallowed fails t unless robotsTxt disallows url for agent.
+func AssertDisallowed(t testing.TB, robotsTxt, agent, url string) {
+	t.Helper()
+	if allowed(robotsTxt, agent, url) {
+		t.Errorf("robotstest: %s allowed for %s, want disallowed", url, agent)
+	}
+}
+
+func allowed(robotsTxt, agent, url string) bool {
+	m := robotstxt.NewMatcher()
+	defer m.Free()
+	return m.IsAllowed(robotsTxt, agent, url)
+}
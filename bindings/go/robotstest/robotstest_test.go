@@ -0,0 +1,110 @@
+package robotstest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	robotstxt "github.com/nzrsky/robotstxt/bindings/go"
+)
+
+// recorder is a testing.TB that records failures instead of reporting them.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestServer(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.Robots("User-agent: *\nDisallow: /private/\n")
+	ctx := context.Background()
+
+	f := &robotstxt.Fetcher{UserAgent: "TestBot/1.0"}
+	res, err := f.Fetch(ctx, srv.URL+"/page")
+	if err != nil || res.StatusCode != http.StatusOK {
+		t.Fatalf("Fetch = %+v, %v", res, err)
+	}
+	AssertDisallowed(t, string(res.Body), "TestBot", srv.URL+"/private/x")
+	AssertAllowed(t, string(res.Body), "TestBot", srv.URL+"/public")
+	srv.AssertHits(t, "/robots.txt", 1)
+	srv.AssertUserAgent(t, "TestBot")
+
+	rec := &recorder{TB: t}
+	AssertAllowed(rec, string(res.Body), "TestBot", srv.URL+"/private/x")
+	srv.AssertHits(rec, "/robots.txt", 2)
+	srv.AssertUserAgent(rec, "OtherBot")
+	if len(rec.errors) != 3 {
+		t.Errorf("Expected 3 failed assertions, got %q", rec.errors)
+	}
+
+	srv.Reset()
+	if res, _ = f.Fetch(ctx, srv.URL); len(srv.Requests()) != 1 {
+		t.Errorf("Requests after Reset = %v", srv.Requests())
+	}
+}
+
+func TestServerSequenceAndFlaky(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.Sequence("/robots.txt",
+		Response{Status: http.StatusServiceUnavailable},
+		Response{Body: "User-agent: *\nAllow: /\n"})
+	f := &robotstxt.Fetcher{}
+
+	var statuses []int
+	for i := 0; i < 3; i++ {
+		res, err := f.Fetch(context.Background(), srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		statuses = append(statuses, res.StatusCode)
+	}
+	if fmt.Sprint(statuses) != "[503 200 200]" {
+		t.Errorf("Sequence statuses = %v", statuses)
+	}
+
+	srv.Reset()
+	srv.Flaky("/robots.txt", 2, Response{Status: http.StatusInternalServerError})
+	statuses = nil
+	for i := 0; i < 4; i++ {
+		res, _ := f.Fetch(context.Background(), srv.URL)
+		statuses = append(statuses, res.StatusCode)
+	}
+	if fmt.Sprint(statuses) != "[503 500 200 500]" {
+		t.Errorf("Flaky statuses = %v", statuses)
+	}
+}
+
+func TestServerRedirectsAndDelay(t *testing.T) {
+	srv := NewTLSServer()
+	defer srv.Close()
+	srv.RedirectChain("/robots.txt", "/final", 3)
+	srv.Handle("/final", Response{Body: "User-agent: *\nDisallow: /\n"})
+	srv.Handle("/slow", Response{Delay: time.Second})
+
+	f := &robotstxt.Fetcher{Client: srv.Client()}
+	res, err := f.Fetch(context.Background(), srv.URL)
+	if err != nil || string(res.Body) != "User-agent: *\nDisallow: /\n" {
+		t.Fatalf("Fetch through chain = %+v, %v", res, err)
+	}
+	if n := len(srv.Requests()); n != 5 {
+		t.Errorf("Expected 5 requests through the chain, got %d", n)
+	}
+
+	f.Locations = map[string]string{srv.URL: "/slow"}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := f.Fetch(ctx, srv.URL); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Fetch(slow) error = %v, want deadline exceeded", err)
+	}
+}
@@ -18,10 +18,17 @@ package robotstxt
 */
 import "C"
 import (
+	"errors"
+	"fmt"
 	"runtime"
+	"sync/atomic"
+	"time"
 	"unsafe"
 )
 
+// ErrFreed is the panic value raised when a Matcher is used after Free.
+var ErrFreed = errors.New("robotstxt: Matcher used after Free")
+
 // Version returns the library version string.
 func Version() string {
 	return C.GoString(C.robots_version())
@@ -38,41 +45,178 @@ func IsValidUserAgent(userAgent string) bool {
 type RequestRate struct {
 	Requests int
 	Seconds  int
+	// Window is the optional visit window, as in "Request-rate: 1/10 0600-1400".
+	// It is nil if the directive has none.
+	Window *TimeWindow
+}
+
+// TimeWindow is a daily time-of-day window in UTC. Start and End are offsets
+// from midnight; an End before Start wraps past midnight.
+type TimeWindow struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Contains reports whether t falls inside the window. The end is exclusive.
+func (w TimeWindow) Contains(t time.Time) bool {
+	t = t.UTC()
+	d := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+	if w.Start <= w.End {
+		return d >= w.Start && d < w.End
+	}
+	return d >= w.Start || d < w.End
+}
+
+// String returns the window in robots.txt form, e.g. "0600-1400".
+func (w TimeWindow) String() string {
+	hhmm := func(d time.Duration) string {
+		m := int(d / time.Minute)
+		return fmt.Sprintf("%02d%02d", m/60, m%60)
+	}
+	return hhmm(w.Start) + "-" + hhmm(w.End)
+}
+
+func newTimeWindow(w C.robots_time_window_t) *TimeWindow {
+	return &TimeWindow{
+		Start: time.Duration(w.start_minute) * time.Minute,
+		End:   time.Duration(w.end_minute) * time.Minute,
+	}
 }
 
 // ContentSignal represents AI content preferences.
-// Values are: nil = not set, true = yes, false = no.
 type ContentSignal struct {
-	AITrain *bool
-	AIInput *bool
-	Search  *bool
+	AITrain TriState `json:"ai_train,omitempty"`
+	AIInput TriState `json:"ai_input,omitempty"`
+	Search  TriState `json:"search,omitempty"`
+}
+
+// Group identifies the user-agent group a per-group value was taken from.
+type Group int
+
+const (
+	// GroupNone means the value was not specified.
+	GroupNone Group = iota
+	// GroupSpecific is the group matching the user-agent.
+	GroupSpecific
+	// GroupGlobal is the global (*) group.
+	GroupGlobal
+)
+
+// CrawlDelayMode selects which groups may supply the crawl-delay.
+type CrawlDelayMode int
+
+const (
+	// CrawlDelayFallback uses the matched group's crawl-delay and falls back
+	// to the global (*) group's when the matched group has none. This is
+	// the library's default behavior.
+	CrawlDelayFallback CrawlDelayMode = iota
+	// CrawlDelayMatchedGroup only uses the crawl-delay of the group that
+	// matched the user-agent; the global group is consulted only when no
+	// specific group matched, following RFC 9309 group selection.
+	CrawlDelayMatchedGroup
+)
+
+// CheckResult is the verdict of a single check together with all per-match
+// state, as returned by Matcher.Check.
+type CheckResult struct {
+	Allowed               bool
+	MatchingLine          int
+	EverSeenSpecificAgent bool
+	// CrawlDelay is in seconds, or nil if not specified.
+	CrawlDelay *float64
+	// CrawlDelayGroup is the group that supplied CrawlDelay.
+	CrawlDelayGroup Group
+	// CrawlDelayLine is the line that supplied CrawlDelay, or 0.
+	CrawlDelayLine int
+	// CrawlDelayRaw is the crawl-delay value as written. CrawlDelayInvalid
+	// is true if it is not a plain non-negative number, e.g. "fast"; the
+	// library then reports CrawlDelay as 0, so a malformed directive can
+	// still be told apart from an absent one.
+	CrawlDelayRaw     string
+	CrawlDelayInvalid bool
+	// RequestRate is nil if not specified.
+	RequestRate *RequestRate
+	// ContentSignal is nil if not specified or not supported.
+	ContentSignal *ContentSignal
 }
 
 // Matcher is a robots.txt matcher that checks if URLs are allowed for given user-agents.
+//
+// The query methods (MatchingLine, CrawlDelay, RequestRate, ContentSignal and
+// friends) report on the most recent IsAllowed or IsAllowedMulti call only,
+// and return zero values after Reset.
+//
+// A Matcher is not safe for concurrent use. Using it after Free panics with
+// ErrFreed.
 type Matcher struct {
-	ptr *C.struct_robots_matcher_s
+	// ptr holds the *C.struct_robots_matcher_s and is swapped atomically so
+	// that Free is idempotent.
+	ptr unsafe.Pointer
+
+	crawlDelayMode CrawlDelayMode
+	groupMode      GroupMode
+	queryMode      QueryMode
+	wildcardLimit  WildcardLimit
 }
 
 // NewMatcher creates a new RobotsMatcher instance.
 // The caller must call Free() when done.
 func NewMatcher() *Matcher {
 	m := &Matcher{
-		ptr: C.robots_matcher_create(),
+		ptr: unsafe.Pointer(C.robots_matcher_create()),
 	}
 	runtime.SetFinalizer(m, (*Matcher).Free)
 	return m
 }
 
-// Free releases the matcher resources.
+// NewMatcherNoFinalizer creates a new RobotsMatcher instance without a GC
+// finalizer. This avoids finalizer overhead when pooling many matchers, but
+// the caller must call Free() or the native matcher is leaked.
+func NewMatcherNoFinalizer() *Matcher {
+	return &Matcher{
+		ptr: unsafe.Pointer(C.robots_matcher_create()),
+	}
+}
+
+// Free releases the matcher resources. It is safe to call Free more than once.
 func (m *Matcher) Free() {
-	if m.ptr != nil {
-		C.robots_matcher_free(m.ptr)
-		m.ptr = nil
+	if p := atomic.SwapPointer(&m.ptr, nil); p != nil {
+		C.robots_matcher_free((*C.struct_robots_matcher_s)(p))
 	}
 }
 
+// Reset clears all per-match state (matching line, crawl-delay, request-rate
+// and content-signal) so the matcher behaves as if freshly created. Options
+// set with SetCrawlDelayMode, SetGroupMode, SetQueryMode and
+// SetWildcardLimit are kept.
+func (m *Matcher) Reset() {
+	C.robots_matcher_reset(m.handle())
+	runtime.KeepAlive(m)
+}
+
+// resetOptions restores every option to its default.
+func (m *Matcher) resetOptions() {
+	m.crawlDelayMode = CrawlDelayFallback
+	m.groupMode = GroupsMerge
+	m.queryMode = QueryInclude
+	m.wildcardLimit = WildcardLimit{}
+}
+
+// handle returns the native matcher, panicking with ErrFreed after Free.
+// Callers must keep m alive until the cgo call returns.
+func (m *Matcher) handle() *C.struct_robots_matcher_s {
+	p := atomic.LoadPointer(&m.ptr)
+	if p == nil {
+		panic(ErrFreed)
+	}
+	return (*C.struct_robots_matcher_s)(p)
+}
+
 // IsAllowed checks if a URL is allowed for a single user-agent.
 func (m *Matcher) IsAllowed(robotsTxt, userAgent, url string) bool {
+	robotsTxt = m.prepare(robotsTxt)
+	url = m.target(url)
 	cRobots := C.CString(robotsTxt)
 	defer C.free(unsafe.Pointer(cRobots))
 	cUA := C.CString(userAgent)
@@ -80,67 +224,214 @@ func (m *Matcher) IsAllowed(robotsTxt, userAgent, url string) bool {
 	cURL := C.CString(url)
 	defer C.free(unsafe.Pointer(cURL))
 
-	return bool(C.robots_allowed_by_robots(
-		m.ptr,
+	allowed := C.robots_allowed_by_robots(
+		m.handle(),
 		cRobots, C.size_t(len(robotsTxt)),
 		cUA, C.size_t(len(userAgent)),
 		cURL, C.size_t(len(url)),
-	))
+	)
+	runtime.KeepAlive(m)
+	return bool(allowed)
+}
+
+// Check checks if a URL is allowed for a single user-agent and returns the
+// verdict with all per-match state from one native call, instead of
+// IsAllowed followed by the individual accessors.
+func (m *Matcher) Check(robotsTxt, userAgent, url string) CheckResult {
+	robotsTxt = m.prepare(robotsTxt)
+	url = m.target(url)
+	cRobots := C.CString(robotsTxt)
+	defer C.free(unsafe.Pointer(cRobots))
+	cUA := C.CString(userAgent)
+	defer C.free(unsafe.Pointer(cUA))
+	cURL := C.CString(url)
+	defer C.free(unsafe.Pointer(cURL))
+
+	var r C.robots_check_result_t
+	C.robots_check(
+		m.handle(),
+		cRobots, C.size_t(len(robotsTxt)),
+		cUA, C.size_t(len(userAgent)),
+		cURL, C.size_t(len(url)),
+		&r,
+	)
+	runtime.KeepAlive(m)
+
+	res := CheckResult{
+		Allowed:               bool(r.allowed),
+		MatchingLine:          int(r.matching_line),
+		EverSeenSpecificAgent: bool(r.ever_seen_specific_agent),
+	}
+	if r.has_crawl_delay {
+		res.CrawlDelayGroup = Group(r.crawl_delay_source)
+		if m.crawlDelayApplies(res.CrawlDelayGroup, res.EverSeenSpecificAgent) {
+			delay := float64(r.crawl_delay)
+			res.CrawlDelay = &delay
+			res.CrawlDelayLine = int(r.crawl_delay_line)
+			res.CrawlDelayRaw = directiveValue(lineAt(robotsTxt, res.CrawlDelayLine))
+			_, err := ParseCrawlDelay(res.CrawlDelayRaw, CrawlDelayOptions{})
+			res.CrawlDelayInvalid = err != nil
+		} else {
+			res.CrawlDelayGroup = GroupNone
+		}
+	}
+	if r.has_request_rate {
+		res.RequestRate = &RequestRate{
+			Requests: int(r.request_rate.requests),
+			Seconds:  int(r.request_rate.seconds),
+		}
+		if r.has_request_rate_window {
+			res.RequestRate.Window = newTimeWindow(r.request_rate_window)
+		}
+	}
+	if r.has_content_signal {
+		res.ContentSignal = newContentSignal(r.content_signal)
+	}
+	return res
+}
+
+// IsAllowedURL is like IsAllowed but first validates url, returning an error
+// wrapping ErrInvalidURL for malformed or relative URLs rather than matching
+// them. Userinfo and fragments are stripped before matching.
+func (m *Matcher) IsAllowedURL(robotsTxt, userAgent, url string) (bool, error) {
+	target, err := matchTarget(url)
+	if err != nil {
+		return false, err
+	}
+	return m.IsAllowed(robotsTxt, userAgent, target), nil
+}
+
+// CheckURL is the Check counterpart of IsAllowedURL.
+func (m *Matcher) CheckURL(robotsTxt, userAgent, url string) (CheckResult, error) {
+	target, err := matchTarget(url)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	return m.Check(robotsTxt, userAgent, target), nil
 }
 
 // IsAllowedMulti checks if a URL is allowed for multiple user-agents.
 func (m *Matcher) IsAllowedMulti(robotsTxt string, userAgents []string, url string) bool {
+	robotsTxt = m.prepare(robotsTxt)
+	url = m.target(url)
 	cRobots := C.CString(robotsTxt)
 	defer C.free(unsafe.Pointer(cRobots))
 	cURL := C.CString(url)
 	defer C.free(unsafe.Pointer(cURL))
 
-	// Prepare user-agent arrays
-	cUAs := make([]*C.char, len(userAgents))
-	cLens := make([]C.size_t, len(userAgents))
+	// Prepare user-agent arrays; the extra element keeps &cUAs[0] valid
+	// when no user-agents are given.
+	cUAs := make([]*C.char, len(userAgents)+1)
+	cLens := make([]C.size_t, len(userAgents)+1)
 	for i, ua := range userAgents {
 		cUAs[i] = C.CString(ua)
 		defer C.free(unsafe.Pointer(cUAs[i]))
 		cLens[i] = C.size_t(len(ua))
 	}
 
-	return bool(C.robots_allowed_by_robots_multi(
-		m.ptr,
+	allowed := C.robots_allowed_by_robots_multi(
+		m.handle(),
 		cRobots, C.size_t(len(robotsTxt)),
 		&cUAs[0], &cLens[0], C.size_t(len(userAgents)),
 		cURL, C.size_t(len(url)),
-	))
+	)
+	runtime.KeepAlive(m)
+	return bool(allowed)
 }
 
 // MatchingLine returns the line number that matched, or 0 if no match.
 func (m *Matcher) MatchingLine() int {
-	return int(C.robots_matching_line(m.ptr))
+	line := C.robots_matching_line(m.handle())
+	runtime.KeepAlive(m)
+	return int(line)
+}
+
+// MatchedRule returns the rule on MatchingLine: its directive ("allow" or
+// "disallow") and pattern as the matcher saw it (percent-escaped), so
+// verdicts can be explained without keeping the robots.txt body. ok is
+// false if no rule matched.
+func (m *Matcher) MatchedRule() (directive, pattern string, line int, ok bool) {
+	var r C.robots_matched_rule_t
+	found := C.robots_matched_rule(m.handle(), &r)
+	defer runtime.KeepAlive(m)
+	if !found {
+		return "", "", 0, false
+	}
+	return DirectiveType(r._type).String(), C.GoStringN(r.pattern, C.int(r.pattern_len)), int(r.line), true
 }
 
 // EverSeenSpecificAgent returns true if a specific user-agent block was found.
 func (m *Matcher) EverSeenSpecificAgent() bool {
-	return bool(C.robots_ever_seen_specific_agent(m.ptr))
+	seen := C.robots_ever_seen_specific_agent(m.handle())
+	runtime.KeepAlive(m)
+	return bool(seen)
+}
+
+// SetCrawlDelayMode selects which groups may supply the crawl-delay
+// reported by CrawlDelay and Check. The default is CrawlDelayFallback;
+// Reset keeps the mode and Pool.Put restores the default.
+func (m *Matcher) SetCrawlDelayMode(mode CrawlDelayMode) {
+	m.crawlDelayMode = mode
 }
 
 // CrawlDelay returns the crawl-delay in seconds, or nil if not specified.
 func (m *Matcher) CrawlDelay() *float64 {
-	if !C.robots_has_crawl_delay(m.ptr) {
+	defer runtime.KeepAlive(m)
+	ptr := m.handle()
+	if !C.robots_has_crawl_delay(ptr) {
+		return nil
+	}
+	if !m.crawlDelayApplies(Group(C.robots_crawl_delay_source(ptr)), bool(C.robots_ever_seen_specific_agent(ptr))) {
 		return nil
 	}
-	delay := float64(C.robots_get_crawl_delay(m.ptr))
+	delay := float64(C.robots_get_crawl_delay(ptr))
 	return &delay
 }
 
+// CrawlDelayLine returns the line number of the crawl-delay reported by
+// CrawlDelay, or 0 if there is none.
+func (m *Matcher) CrawlDelayLine() int {
+	if m.CrawlDelayGroup() == GroupNone {
+		return 0
+	}
+	defer runtime.KeepAlive(m)
+	return int(C.robots_crawl_delay_line(m.handle()))
+}
+
+// CrawlDelayGroup returns the group that supplied the crawl-delay reported
+// by CrawlDelay, or GroupNone if there is none.
+func (m *Matcher) CrawlDelayGroup() Group {
+	defer runtime.KeepAlive(m)
+	ptr := m.handle()
+	group := Group(C.robots_crawl_delay_source(ptr))
+	if !m.crawlDelayApplies(group, bool(C.robots_ever_seen_specific_agent(ptr))) {
+		return GroupNone
+	}
+	return group
+}
+
+// crawlDelayApplies reports whether a crawl-delay from group is used under
+// the matcher's CrawlDelayMode.
+func (m *Matcher) crawlDelayApplies(group Group, everSeenSpecificAgent bool) bool {
+	return m.crawlDelayMode != CrawlDelayMatchedGroup || group != GroupGlobal || !everSeenSpecificAgent
+}
+
 // RequestRate returns the request-rate, or nil if not specified.
 func (m *Matcher) RequestRate() *RequestRate {
+	defer runtime.KeepAlive(m)
 	var rate C.robots_request_rate_t
-	if !C.robots_get_request_rate(m.ptr, &rate) {
+	if !C.robots_get_request_rate(m.handle(), &rate) {
 		return nil
 	}
-	return &RequestRate{
+	rr := &RequestRate{
 		Requests: int(rate.requests),
 		Seconds:  int(rate.seconds),
 	}
+	var w C.robots_time_window_t
+	if C.robots_get_request_rate_window(m.handle(), &w) {
+		rr.Window = newTimeWindow(w)
+	}
+	return rr
 }
 
 // ContentSignalSupported returns true if Content-Signal support is compiled in.
@@ -150,20 +441,28 @@ func ContentSignalSupported() bool {
 
 // ContentSignal returns the content-signal values, or nil if not specified.
 func (m *Matcher) ContentSignal() *ContentSignal {
+	defer runtime.KeepAlive(m)
+	ptr := m.handle()
 	if !C.robots_content_signal_supported() {
 		return nil
 	}
 	var signal C.robots_content_signal_t
-	if !C.robots_get_content_signal(m.ptr, &signal) {
+	if !C.robots_get_content_signal(ptr, &signal) {
 		return nil
 	}
 
-	triState := func(v C.int8_t) *bool {
-		if v == -1 {
-			return nil
+	return newContentSignal(signal)
+}
+
+func newContentSignal(signal C.robots_content_signal_t) *ContentSignal {
+	triState := func(v C.int8_t) TriState {
+		switch v {
+		case -1:
+			return Unset
+		case 1:
+			return Yes
 		}
-		b := v == 1
-		return &b
+		return No
 	}
 
 	return &ContentSignal{
@@ -175,15 +474,21 @@ func (m *Matcher) ContentSignal() *ContentSignal {
 
 // AllowsAITrain returns true if AI training is allowed (defaults to true if not specified).
 func (m *Matcher) AllowsAITrain() bool {
-	return bool(C.robots_allows_ai_train(m.ptr))
+	allowed := C.robots_allows_ai_train(m.handle())
+	runtime.KeepAlive(m)
+	return bool(allowed)
 }
 
 // AllowsAIInput returns true if AI input is allowed (defaults to true if not specified).
 func (m *Matcher) AllowsAIInput() bool {
-	return bool(C.robots_allows_ai_input(m.ptr))
+	allowed := C.robots_allows_ai_input(m.handle())
+	runtime.KeepAlive(m)
+	return bool(allowed)
 }
 
 // AllowsSearch returns true if search indexing is allowed (defaults to true if not specified).
 func (m *Matcher) AllowsSearch() bool {
-	return bool(C.robots_allows_search(m.ptr))
+	allowed := C.robots_allows_search(m.handle())
+	runtime.KeepAlive(m)
+	return bool(allowed)
 }
@@ -1,7 +1,10 @@
 package robotstxt
 
 import (
+	"errors"
+	"runtime"
 	"testing"
+	"time"
 )
 
 func TestVersion(t *testing.T) {
@@ -105,6 +108,39 @@ func TestRequestRate(t *testing.T) {
 	if rate.Requests != 1 || rate.Seconds != 10 {
 		t.Errorf("Expected 1/10, got %d/%d", rate.Requests, rate.Seconds)
 	}
+	if rate.Window != nil {
+		t.Errorf("Expected no window, got %v", rate.Window)
+	}
+}
+
+func TestRequestRateWindow(t *testing.T) {
+	m := NewMatcher()
+	defer m.Free()
+
+	robotsTxt := "User-agent: *\nRequest-rate: 1/10 0600-1400\n"
+	m.IsAllowed(robotsTxt, "Googlebot", "https://example.com/")
+
+	rate := m.RequestRate()
+	if rate == nil || rate.Window == nil {
+		t.Fatalf("Expected request-rate window, got %+v", rate)
+	}
+	if got := rate.Window.String(); got != "0600-1400" {
+		t.Errorf("Expected window 0600-1400, got %s", got)
+	}
+	res := m.Check(robotsTxt, "Googlebot", "https://example.com/")
+	if res.RequestRate == nil || res.RequestRate.Window == nil || *res.RequestRate.Window != *rate.Window {
+		t.Errorf("Check window mismatch: %+v", res.RequestRate)
+	}
+
+	at := func(h, min int) time.Time { return time.Date(2024, 1, 1, h, min, 0, 0, time.UTC) }
+	w := *rate.Window
+	if !w.Contains(at(6, 0)) || !w.Contains(at(13, 59)) || w.Contains(at(14, 0)) || w.Contains(at(5, 59)) {
+		t.Error("Contains mismatch for 0600-1400")
+	}
+	overnight := TimeWindow{Start: 22 * time.Hour, End: 6 * time.Hour}
+	if !overnight.Contains(at(23, 0)) || !overnight.Contains(at(1, 0)) || overnight.Contains(at(12, 0)) {
+		t.Error("Contains mismatch for overnight window")
+	}
 }
 
 func TestContentSignal(t *testing.T) {
@@ -123,13 +159,295 @@ func TestContentSignal(t *testing.T) {
 		t.Fatal("Expected content-signal to be set")
 	}
 
-	if signal.AITrain == nil || *signal.AITrain != false {
+	if signal.AITrain != No {
 		t.Error("Expected ai-train=no")
 	}
-	if signal.Search == nil || *signal.Search != true {
+	if signal.Search != Yes {
 		t.Error("Expected search=yes")
 	}
-	if signal.AIInput != nil {
+	if signal.AIInput != Unset {
 		t.Error("Expected ai-input to be unset")
 	}
 }
+
+func TestFreeIdempotent(t *testing.T) {
+	m := NewMatcher()
+	m.Free()
+	m.Free()
+}
+
+func TestUseAfterFree(t *testing.T) {
+	m := NewMatcher()
+	m.Free()
+
+	defer func() {
+		r := recover()
+		err, ok := r.(error)
+		if !ok || !errors.Is(err, ErrFreed) {
+			t.Errorf("Expected panic with ErrFreed, got %v", r)
+		}
+	}()
+	m.IsAllowed("User-agent: *\nDisallow: /\n", "Googlebot", "https://example.com/")
+}
+
+func TestNewMatcherNoFinalizer(t *testing.T) {
+	m := NewMatcherNoFinalizer()
+	defer m.Free()
+
+	if m.IsAllowed("User-agent: *\nDisallow: /\n", "Googlebot", "https://example.com/") {
+		t.Error("Expected URL to be disallowed")
+	}
+}
+
+func BenchmarkNewMatcher(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m := NewMatcher()
+		m.Free()
+	}
+}
+
+func BenchmarkNewMatcherNoFinalizer(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m := NewMatcherNoFinalizer()
+		m.Free()
+	}
+}
+
+// BenchmarkMatcherGC measures what the finalizer costs under GC pressure,
+// which the allocate-and-Free loops above do not show: each op creates a
+// batch of matchers, drops them (freeing only those without a finalizer)
+// and runs a collection. gc-pause-ns/op is the stop-the-world pause time.
+func BenchmarkMatcherGC(b *testing.B) {
+	const batch = 1000
+	for _, bc := range []struct {
+		name string
+		fill func(ms []*Matcher)
+	}{
+		{"Finalizer", func(ms []*Matcher) {
+			for i := range ms {
+				ms[i] = NewMatcher()
+			}
+		}},
+		{"NoFinalizer", func(ms []*Matcher) {
+			for i := range ms {
+				ms[i] = NewMatcherNoFinalizer()
+			}
+			for _, m := range ms {
+				m.Free()
+			}
+		}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			ms := make([]*Matcher, batch)
+			var before, after runtime.MemStats
+			runtime.GC()
+			runtime.ReadMemStats(&before)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				bc.fill(ms)
+				for j := range ms {
+					ms[j] = nil
+				}
+				runtime.GC()
+			}
+			b.StopTimer()
+			runtime.ReadMemStats(&after)
+			b.ReportMetric(float64(after.PauseTotalNs-before.PauseTotalNs)/float64(b.N), "gc-pause-ns/op")
+		})
+	}
+}
+
+func TestMatchedRule(t *testing.T) {
+	m := NewMatcher()
+	defer m.Free()
+
+	robotsTxt := "User-agent: *\nDisallow: /a\n\nUser-agent: Googlebot\nDisallow: /private/\nAllow: /private/p\u00e4ge\nDisallow: /*.pdf$\n"
+	tests := []struct {
+		agent, url         string
+		directive, pattern string
+		line               int
+	}{
+		{"Googlebot", "https://example.com/private/x", "disallow", "/private/", 5},
+		{"Googlebot", "https://example.com/private/p%C3%A4ge", "allow", "/private/p%C3%A4ge", 6},
+		{"Googlebot", "https://example.com/private/x.pdf", "disallow", "/private/", 5},
+		{"Googlebot", "https://example.com/x.pdf", "disallow", "/*.pdf$", 7},
+		{"OtherBot", "https://example.com/a/b", "disallow", "/a", 2},
+	}
+	for _, tt := range tests {
+		m.IsAllowed(robotsTxt, tt.agent, tt.url)
+		directive, pattern, line, ok := m.MatchedRule()
+		if !ok || directive != tt.directive || pattern != tt.pattern || line != tt.line || line != m.MatchingLine() {
+			t.Errorf("%s %s: MatchedRule = %q %q %d %v, want %q %q %d", tt.agent, tt.url, directive, pattern, line, ok, tt.directive, tt.pattern, tt.line)
+		}
+	}
+
+	// A specific group without a matching rule hides the global match.
+	m.IsAllowed(robotsTxt, "Googlebot", "https://example.com/a")
+	if _, _, _, ok := m.MatchedRule(); ok {
+		t.Error("MatchedRule reported a rule from the * group for Googlebot")
+	}
+	m.Reset()
+	if _, _, _, ok := m.MatchedRule(); ok {
+		t.Error("MatchedRule survived Reset")
+	}
+}
+
+func TestStateDoesNotLeak(t *testing.T) {
+	m := NewMatcher()
+	defer m.Free()
+
+	m.IsAllowed("User-agent: *\nCrawl-delay: 5\nRequest-rate: 1/10\nDisallow: /a\n", "Googlebot", "https://example.com/a")
+	if m.CrawlDelay() == nil || m.RequestRate() == nil || m.MatchingLine() == 0 {
+		t.Fatal("Expected state from the first document")
+	}
+
+	m.IsAllowed("User-agent: *\nDisallow: /b\n", "Googlebot", "https://example.com/a")
+	if m.CrawlDelay() != nil || m.RequestRate() != nil || m.MatchingLine() != 0 {
+		t.Error("Expected no state from the first document to leak into the second")
+	}
+}
+
+func TestReset(t *testing.T) {
+	m := NewMatcher()
+	defer m.Free()
+
+	m.IsAllowed("User-agent: Googlebot\nCrawl-delay: 5\nDisallow: /a\n", "Googlebot", "https://example.com/a")
+	m.Reset()
+	if m.CrawlDelay() != nil || m.MatchingLine() != 0 || m.EverSeenSpecificAgent() {
+		t.Error("Expected Reset to clear all state")
+	}
+	if !m.IsAllowed("User-agent: *\nAllow: /\n", "Googlebot", "https://example.com/") {
+		t.Error("Expected matcher to be usable after Reset")
+	}
+}
+
+func TestResetKeepsOptions(t *testing.T) {
+	m := NewMatcher()
+	defer m.Free()
+	robotsTxt := "User-agent: *\nCrawl-delay: 5\n\nUser-agent: Googlebot\nDisallow: /private/\n\n" +
+		"User-agent: Googlebot\nAllow: /\nDisallow: /*?\nDisallow: /*x*y\n"
+
+	m.SetCrawlDelayMode(CrawlDelayMatchedGroup)
+	m.SetGroupMode(GroupsLastWins)
+	m.SetQueryMode(QueryExclude)
+	m.SetWildcardLimit(WildcardLimit{MaxWildcards: 1})
+	m.Reset()
+
+	if !m.IsAllowed(robotsTxt, "Googlebot", "https://example.com/private/") {
+		t.Error("Expected GroupsLastWins to survive Reset")
+	}
+	if m.CrawlDelay() != nil {
+		t.Error("Expected CrawlDelayMatchedGroup to survive Reset")
+	}
+	if !m.IsAllowed(robotsTxt, "Googlebot", "https://example.com/page?q=1") {
+		t.Error("Expected QueryExclude to survive Reset")
+	}
+	if !m.IsAllowed(robotsTxt, "Googlebot", "https://example.com/axby") {
+		t.Error("Expected WildcardLimit to survive Reset")
+	}
+}
+
+func TestIsAllowedMultiNoAgents(t *testing.T) {
+	m := NewMatcher()
+	defer m.Free()
+
+	if m.IsAllowedMulti("User-agent: *\nDisallow: /\n", nil, "https://example.com/") {
+		t.Error("Expected global rules to apply without user-agents")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	m := NewMatcher()
+	defer m.Free()
+
+	robotsTxt := "User-agent: Googlebot\nCrawl-delay: 2\nRequest-rate: 3/60\nDisallow: /private/\n"
+	res := m.Check(robotsTxt, "Googlebot", "https://example.com/private/x")
+	if res.Allowed {
+		t.Error("Expected /private/x to be disallowed")
+	}
+	if res.MatchingLine != 4 || !res.EverSeenSpecificAgent {
+		t.Errorf("Unexpected match state %+v", res)
+	}
+	if res.CrawlDelay == nil || *res.CrawlDelay != 2 {
+		t.Errorf("Expected crawl-delay 2, got %v", res.CrawlDelay)
+	}
+	if res.RequestRate == nil || *res.RequestRate != (RequestRate{Requests: 3, Seconds: 60}) {
+		t.Errorf("Expected request-rate 3/60, got %v", res.RequestRate)
+	}
+	if res.ContentSignal != nil {
+		t.Errorf("Expected no content-signal, got %+v", res.ContentSignal)
+	}
+
+	res = m.Check("User-agent: *\nDisallow:\n", "Googlebot", "https://example.com/")
+	if !res.Allowed || res.CrawlDelay != nil || res.RequestRate != nil {
+		t.Errorf("Unexpected result %+v", res)
+	}
+}
+
+func BenchmarkIsAllowedThenAccessors(b *testing.B) {
+	m := NewMatcher()
+	defer m.Free()
+	robotsTxt := "User-agent: *\nCrawl-delay: 2\nDisallow: /private/\n"
+	for i := 0; i < b.N; i++ {
+		m.IsAllowed(robotsTxt, "Googlebot", "https://example.com/private/x")
+		m.MatchingLine()
+		m.CrawlDelay()
+		m.RequestRate()
+		m.ContentSignal()
+	}
+}
+
+func BenchmarkCheck(b *testing.B) {
+	m := NewMatcher()
+	defer m.Free()
+	robotsTxt := "User-agent: *\nCrawl-delay: 2\nDisallow: /private/\n"
+	for i := 0; i < b.N; i++ {
+		m.Check(robotsTxt, "Googlebot", "https://example.com/private/x")
+	}
+}
+
+func TestCrawlDelayMode(t *testing.T) {
+	m := NewMatcher()
+	defer m.Free()
+
+	robotsTxt := `
+User-agent: *
+Crawl-delay: 10
+
+User-agent: FooBot
+Crawl-delay: 5
+
+User-agent: BarBot
+Disallow: /private/
+`
+	tests := []struct {
+		mode  CrawlDelayMode
+		agent string
+		delay float64 // 0 means no crawl-delay
+		group Group
+	}{
+		{CrawlDelayFallback, "FooBot", 5, GroupSpecific},
+		{CrawlDelayFallback, "BarBot", 10, GroupGlobal},
+		{CrawlDelayFallback, "OtherBot", 10, GroupGlobal},
+		{CrawlDelayMatchedGroup, "FooBot", 5, GroupSpecific},
+		{CrawlDelayMatchedGroup, "BarBot", 0, GroupNone},
+		{CrawlDelayMatchedGroup, "OtherBot", 10, GroupGlobal},
+	}
+	for _, tt := range tests {
+		m.SetCrawlDelayMode(tt.mode)
+		res := m.Check(robotsTxt, tt.agent, "https://example.com/")
+		m.IsAllowed(robotsTxt, tt.agent, "https://example.com/")
+		for _, got := range []struct {
+			delay *float64
+			group Group
+		}{{res.CrawlDelay, res.CrawlDelayGroup}, {m.CrawlDelay(), m.CrawlDelayGroup()}} {
+			if (got.delay == nil) != (tt.delay == 0) || (got.delay != nil && *got.delay != tt.delay) || got.group != tt.group {
+				t.Errorf("mode %d, %s: got delay %v from group %d, want %v from group %d",
+					tt.mode, tt.agent, got.delay, got.group, tt.delay, tt.group)
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package robotstxt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule is a single Allow or Disallow rule of a user-agent group.
+type Rule struct {
+	// UserAgent is the group the rule belongs to, "*" for all agents.
+	UserAgent string
+	// Allow is true for Allow rules and false for Disallow rules.
+	Allow bool
+	// Pattern is the path pattern, possibly with '*' and '$'.
+	Pattern string
+}
+
+// Evaluation is the result of EvaluateRules.
+type Evaluation struct {
+	Allowed bool
+	// Rule is the index of the rule that decided the verdict, or -1 if no
+	// rule matched.
+	Rule int
+}
+
+var evalPool Pool
+
+// EvaluateRules checks url for userAgent against a hypothetical rule set with
+// exactly the same semantics as a robots.txt file containing those rules.
+// Consecutive rules with the same UserAgent form one group.
+func EvaluateRules(rules []Rule, userAgent, url string) (Evaluation, error) {
+	body, lines, err := renderRules(rules)
+	if err != nil {
+		return Evaluation{}, err
+	}
+
+	m := evalPool.Get()
+	defer evalPool.Put(m)
+	eval := Evaluation{
+		Allowed: m.IsAllowed(body, userAgent, url),
+		Rule:    -1,
+	}
+	if line := m.MatchingLine(); line > 0 && line <= len(lines) {
+		eval.Rule = lines[line-1]
+	}
+	return eval, nil
+}
+
+// renderRules serializes rules into a robots.txt body. lines maps each line
+// (0-based) to the index of the rule on it, or -1 for user-agent lines.
+func renderRules(rules []Rule) (body string, lines []int, err error) {
+	var b strings.Builder
+	for i, r := range rules {
+		if r.UserAgent == "" || strings.ContainsAny(r.UserAgent, "\r\n#") {
+			return "", nil, fmt.Errorf("robotstxt: invalid rule user-agent %q", r.UserAgent)
+		}
+		if strings.ContainsAny(r.Pattern, "\r\n#") {
+			return "", nil, fmt.Errorf("robotstxt: invalid rule pattern %q", r.Pattern)
+		}
+		if i == 0 || rules[i-1].UserAgent != r.UserAgent {
+			if i > 0 {
+				b.WriteString("\n")
+				lines = append(lines, -1)
+			}
+			b.WriteString("User-agent: " + r.UserAgent + "\n")
+			lines = append(lines, -1)
+		}
+		if r.Allow {
+			b.WriteString("Allow: ")
+		} else {
+			b.WriteString("Disallow: ")
+		}
+		b.WriteString(r.Pattern + "\n")
+		lines = append(lines, i)
+	}
+	return b.String(), lines, nil
+}
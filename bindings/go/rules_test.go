@@ -0,0 +1,53 @@
+package robotstxt
+
+import "testing"
+
+func TestEvaluateRules(t *testing.T) {
+	rules := []Rule{
+		{UserAgent: "*", Allow: false, Pattern: "/"},
+		{UserAgent: "Googlebot", Allow: true, Pattern: "/"},
+		{UserAgent: "Googlebot", Allow: false, Pattern: "/admin/"},
+		{UserAgent: "Googlebot", Allow: true, Pattern: "/admin/public$"},
+	}
+
+	tests := []struct {
+		agent   string
+		url     string
+		allowed bool
+		rule    int
+	}{
+		{"Googlebot", "https://example.com/page", true, 1},
+		{"Googlebot", "https://example.com/admin/x", false, 2},
+		{"Googlebot", "https://example.com/admin/public", true, 3},
+		{"Bingbot", "https://example.com/page", false, 0},
+	}
+	for _, tt := range tests {
+		eval, err := EvaluateRules(rules, tt.agent, tt.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if eval.Allowed != tt.allowed || eval.Rule != tt.rule {
+			t.Errorf("EvaluateRules(%s, %s) = %+v, want allowed=%v rule=%d",
+				tt.agent, tt.url, eval, tt.allowed, tt.rule)
+		}
+	}
+}
+
+func TestEvaluateRulesNoMatch(t *testing.T) {
+	eval, err := EvaluateRules([]Rule{{UserAgent: "*", Pattern: "/private/"}}, "Googlebot", "https://example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !eval.Allowed || eval.Rule != -1 {
+		t.Errorf("Expected allowed with no rule, got %+v", eval)
+	}
+}
+
+func TestEvaluateRulesInvalid(t *testing.T) {
+	if _, err := EvaluateRules([]Rule{{UserAgent: "*", Pattern: "/a\nDisallow: /"}}, "Googlebot", "https://example.com/"); err == nil {
+		t.Error("Expected error for pattern with newline")
+	}
+	if _, err := EvaluateRules([]Rule{{Pattern: "/"}}, "Googlebot", "https://example.com/"); err == nil {
+		t.Error("Expected error for empty user-agent")
+	}
+}
@@ -0,0 +1,73 @@
+package robotstxt
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+)
+
+// SelftestCase is one conformance case run by Selftest.
+type SelftestCase struct {
+	Name    string `json:"name"`
+	Robots  string `json:"robots"`
+	Agent   string `json:"agent"`
+	URL     string `json:"url"`
+	Allowed bool   `json:"allowed"`
+}
+
+// SelftestResult is the outcome of one case.
+type SelftestResult struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	// Allowed is the verdict the native library returned.
+	Allowed bool `json:"allowed"`
+}
+
+// SelftestReport is the outcome of Selftest.
+type SelftestReport struct {
+	// Version is the native library version.
+	Version string           `json:"version"`
+	Passed  bool             `json:"passed"`
+	Results []SelftestResult `json:"results"`
+}
+
+//go:embed selftest.json
+var selftestJSON []byte
+
+// SelftestCases returns the embedded conformance corpus: RFC 9309 group
+// and rule semantics plus the library's documented leniencies.
+func SelftestCases() []SelftestCase {
+	var cases []SelftestCase
+	if err := json.Unmarshal(selftestJSON, &cases); err != nil {
+		panic("robotstxt: invalid embedded selftest.json: " + err.Error())
+	}
+	return cases
+}
+
+// Selftest runs the embedded conformance corpus through the linked native
+// library, so a deployment can verify at startup that the library it was
+// built against behaves as expected.
+func Selftest() SelftestReport {
+	m := NewMatcher()
+	defer m.Free()
+	rep := SelftestReport{Version: Version(), Passed: true}
+	for _, c := range SelftestCases() {
+		got := m.IsAllowed(c.Robots, c.Agent, c.URL)
+		rep.Results = append(rep.Results, SelftestResult{Name: c.Name, Passed: got == c.Allowed, Allowed: got})
+		rep.Passed = rep.Passed && got == c.Allowed
+	}
+	return rep
+}
+
+// SelftestHandler serves the Selftest report as JSON, with status 200 if
+// every case passed and 500 otherwise, for health checks.
+func SelftestHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rep := Selftest()
+		w.Header().Set("Content-Type", "application/json")
+		if !rep.Passed {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		json.NewEncoder(w).Encode(rep)
+	})
+}
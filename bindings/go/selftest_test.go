@@ -0,0 +1,35 @@
+package robotstxt
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSelftest(t *testing.T) {
+	rep := Selftest()
+	if rep.Version == "" || len(rep.Results) != len(SelftestCases()) {
+		t.Fatalf("Unexpected report %+v", rep)
+	}
+	for _, r := range rep.Results {
+		if !r.Passed {
+			t.Errorf("Case %q failed: allowed = %v", r.Name, r.Allowed)
+		}
+	}
+	if !rep.Passed {
+		t.Error("Expected the report to pass")
+	}
+}
+
+func TestSelftestHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	SelftestHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/selftest", nil))
+	var rep SelftestReport
+	if err := json.Unmarshal(w.Body.Bytes(), &rep); err != nil {
+		t.Fatal(err)
+	}
+	if w.Code != http.StatusOK || !rep.Passed || w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Unexpected response %d %+v", w.Code, rep)
+	}
+}
@@ -0,0 +1,125 @@
+package robotstxt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// ErrBlockedAddress is returned by Fetch when Fetcher.BlockPrivate refuses
+// the address a host resolves to.
+var ErrBlockedAddress = errors.New("robotstxt: address not allowed")
+
+// cgnat is the shared address space of RFC 6598, private in practice.
+var cgnat = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// blockedIP reports whether ip is loopback, private, link-local, multicast,
+// unspecified or carrier-grade NAT space.
+func blockedIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || cgnat.Contains(ip)
+}
+
+// guarded reports whether HTTP fetches need a transport with f's dialer.
+func (f *Fetcher) guarded() bool {
+	return f.BlockPrivate || f.PinDNS || f.Dial != nil
+}
+
+// transport returns a copy of base that dials through f's guards. Keep-alives
+// are off so the copy holds no connections once the body is closed, and
+// proxies are bypassed when addresses are checked, since the target address
+// would not be.
+func (f *Fetcher) transport(base http.RoundTripper) (*http.Transport, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	bt, ok := base.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("robotstxt: BlockPrivate, PinDNS and Dial need an *http.Transport, have %T", base)
+	}
+	t := bt.Clone()
+	t.DisableKeepAlives = true
+	if f.BlockPrivate || f.PinDNS {
+		t.Proxy = nil
+	}
+	d := &guardedDialer{f: f, pins: make(map[string][]net.IP)}
+	t.DialContext = d.dial
+	t.DialTLSContext = nil
+	return t, nil
+}
+
+// guardedDialer resolves and checks addresses for one Fetch. Hosts resolved
+// with PinDNS keep their addresses for the rest of the fetch, redirects
+// included.
+type guardedDialer struct {
+	f    *Fetcher
+	mu   sync.Mutex
+	pins map[string][]net.IP
+}
+
+func (d *guardedDialer) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	dial := d.f.Dial
+	if dial == nil {
+		dial = (&net.Dialer{}).DialContext
+	}
+	if !d.f.BlockPrivate && !d.f.PinDNS {
+		return dial(ctx, network, addr)
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	ips, err := d.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if d.f.BlockPrivate {
+		for _, ip := range ips {
+			if blockedIP(ip) {
+				return nil, fmt.Errorf("%w: %s resolves to %s", ErrBlockedAddress, host, ip)
+			}
+		}
+	}
+	// Dial the checked addresses, not the name, so a second lookup cannot
+	// return a different answer.
+	for _, ip := range ips {
+		var conn net.Conn
+		if conn, err = dial(ctx, network, net.JoinHostPort(ip.String(), port)); err == nil {
+			return conn, nil
+		}
+	}
+	return nil, err
+}
+
+// resolve returns the addresses of host, pinned if PinDNS is set.
+func (d *guardedDialer) resolve(ctx context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+	d.mu.Lock()
+	ips, ok := d.pins[host]
+	d.mu.Unlock()
+	if ok {
+		return ips, nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range addrs {
+		ips = append(ips, a.IP)
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("robotstxt: no addresses for %s", host)
+	}
+	if d.f.PinDNS {
+		d.mu.Lock()
+		d.pins[host] = ips
+		d.mu.Unlock()
+	}
+	return ips, nil
+}
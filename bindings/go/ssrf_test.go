@@ -0,0 +1,102 @@
+package robotstxt
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestBlockedIP(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"127.0.0.1":       true,
+		"10.1.2.3":        true,
+		"172.16.0.1":      true,
+		"192.168.1.1":     true,
+		"169.254.169.254": true,
+		"100.64.0.1":      true,
+		"0.0.0.0":         true,
+		"::1":             true,
+		"fe80::1":         true,
+		"fd00::1":         true,
+		"::ffff:10.0.0.1": true,
+		"8.8.8.8":         false,
+		"2001:4860::8888": false,
+	} {
+		if got := blockedIP(net.ParseIP(addr)); got != want {
+			t.Errorf("blockedIP(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestFetchSSRFGuards(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("User-agent: *\nDisallow: /\n"))
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	f := &Fetcher{BlockPrivate: true}
+	if _, err := f.Fetch(ctx, srv.URL); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("Fetch(loopback, BlockPrivate) error = %v, want ErrBlockedAddress", err)
+	}
+
+	var mu sync.Mutex
+	var dialed []string
+	f = &Fetcher{PinDNS: true, Dial: func(ctx context.Context, network, addr string) (net.Conn, error) {
+		mu.Lock()
+		dialed = append(dialed, addr)
+		mu.Unlock()
+		return (&net.Dialer{}).DialContext(ctx, network, addr)
+	}}
+	_, port, _ := net.SplitHostPort(strings.TrimPrefix(srv.URL, "http://"))
+	res, err := f.Fetch(ctx, "http://localhost:"+port+"/")
+	if err != nil || res.StatusCode != http.StatusOK {
+		t.Fatalf("Fetch(localhost, PinDNS) = %+v, %v", res, err)
+	}
+	if len(dialed) == 0 {
+		t.Fatal("Dial hook not called")
+	}
+	for _, addr := range dialed {
+		if host, _, _ := net.SplitHostPort(addr); net.ParseIP(host) == nil {
+			t.Errorf("Dial got %q, want a resolved IP address", addr)
+		}
+	}
+
+	f = &Fetcher{Dial: func(context.Context, string, string) (net.Conn, error) {
+		return nil, errors.New("dial refused")
+	}}
+	if _, err := f.Fetch(ctx, srv.URL); err == nil || !strings.Contains(err.Error(), "dial refused") {
+		t.Errorf("Fetch with failing Dial error = %v", err)
+	}
+}
+
+func TestFetchSSRFGuardsSchemes(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	f := &Fetcher{BlockPrivate: true}
+	for _, u := range []string{"ftp://10.0.0.5:6379/", "ftp://" + serveFTP(t, nil, false) + "/"} {
+		if _, err := f.Fetch(ctx, u); !errors.Is(err, ErrBlockedAddress) {
+			t.Errorf("Fetch(%s, BlockPrivate) error = %v, want ErrBlockedAddress", u, err)
+		}
+	}
+
+	res, err := (&Fetcher{PinDNS: true}).Fetch(ctx, "ftp://"+serveFTP(t, nil, false)+"/")
+	if err != nil || res.StatusCode != http.StatusNotFound {
+		t.Errorf("Fetch(ftp, PinDNS) = %+v, %v", res, err)
+	}
+
+	f.Schemes = map[string]SchemeFetcher{"gopher": FTPFetcher{Dial: (&net.Dialer{}).DialContext}}
+	if _, err := f.Fetch(ctx, "gopher://example.com/"); !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("Fetch(custom scheme, BlockPrivate) error = %v, want ErrBlockedAddress", err)
+	}
+	if _, err := (&Fetcher{BlockPrivate: true, Mirror: fstest.MapFS{}}).Fetch(ctx, "ftp://10.0.0.5/"); err != nil {
+		t.Errorf("Fetch(Mirror, BlockPrivate) error = %v", err)
+	}
+}
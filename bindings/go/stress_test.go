@@ -0,0 +1,122 @@
+package robotstxt
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// The stress tests hammer the concurrency-safe types from many goroutines
+// with mixed operations. They are meant to be run with -race and are
+// skipped with -short.
+
+const stressGoroutines = 200
+
+func TestStressFetchCheckInvalidate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("stress test")
+	}
+	var store TenantStore
+	tenants := []Tenant{
+		{Rules: []Rule{{UserAgent: "*", Pattern: "/private/"}}},
+		{Rules: []Rule{{UserAgent: "*", Pattern: "/private/"}, {UserAgent: "FooBot", Pattern: "/private/"}}},
+	}
+	if err := store.Set("127.0.0.1", tenants[0]); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(&store)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "overrides.json")
+	writeOverrides := func(i int) error {
+		tmp := filepath.Join(dir, "tmp"+strconv.Itoa(i))
+		data := `{"https://o` + strconv.Itoa(i%3) + `.example": {"action": "deny"}}`
+		if err := os.WriteFile(tmp, []byte(data), 0o644); err != nil {
+			return err
+		}
+		return os.Rename(tmp, path)
+	}
+	if err := writeOverrides(0); err != nil {
+		t.Fatal(err)
+	}
+	overrides, err := LoadOverrides(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		pool    Pool
+		fetcher = Fetcher{Client: srv.Client()}
+		slots   MemoryPolitenessStore
+		ctx     = context.Background()
+		wg      sync.WaitGroup
+	)
+	for g := 0; g < stressGoroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				switch (g + i) % 5 {
+				case 0, 1:
+					res, err := fetcher.Fetch(ctx, srv.URL+"/page")
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					if res.StatusCode != 200 {
+						continue
+					}
+					m := pool.Get()
+					if m.IsAllowed(string(res.Body), "FooBot", srv.URL+"/private/x") ||
+						!m.IsAllowed(string(res.Body), "FooBot", srv.URL+"/public") {
+						t.Errorf("Unexpected verdicts for %q", res.Body)
+					}
+					pool.Put(m)
+				case 2:
+					if i%2 == 0 {
+						store.Delete("127.0.0.1")
+					} else if err := store.Set("127.0.0.1", tenants[g%2]); err != nil {
+						t.Error(err)
+					}
+				case 3:
+					if g%20 == 0 {
+						if err := writeOverrides(g*100 + i); err != nil {
+							t.Error(err)
+						}
+						overrides.Reload()
+					}
+					overrides.Lookup("https://o1.example/x")
+				case 4:
+					if _, err := slots.Reserve(ctx, srv.URL, 0); err != nil {
+						t.Error(err)
+					}
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestStressMatcherFree(t *testing.T) {
+	if testing.Short() {
+		t.Skip("stress test")
+	}
+	// Free is idempotent and safe to race with itself.
+	for n := 0; n < 20; n++ {
+		m := NewMatcherNoFinalizer()
+		var wg sync.WaitGroup
+		for g := 0; g < stressGoroutines/10; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				m.Free()
+			}()
+		}
+		wg.Wait()
+	}
+}
@@ -0,0 +1,206 @@
+package robotstxt
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tenant is the robots.txt configuration of one hosted site.
+type Tenant struct {
+	Rules    []Rule
+	Sitemaps []string
+	// ContentSignal, if set, is declared in a trailing "User-agent: *" group.
+	ContentSignal *ContentSignal
+	// AgentSignals declare Content-Signal per user-agent, keyed by the
+	// UserAgent as written in Rules. Each goes at the top of the agent's
+	// first group. An agent without rules gets a group of its own with a
+	// copy of the "*" rules, so its verdicts do not change. Use
+	// ContentSignal for "*".
+	AgentSignals map[string]*ContentSignal
+}
+
+// Render returns the tenant's robots.txt.
+func (t Tenant) Render() (string, error) {
+	body, _, err := renderRules(t.Rules)
+	if err != nil {
+		return "", err
+	}
+	if body, err = renderAgentSignals(body, t.Rules, t.AgentSignals); err != nil {
+		return "", err
+	}
+	if cs := renderContentSignal(t.ContentSignal); cs != "" {
+		if body != "" {
+			body += "\n"
+		}
+		body += "User-agent: *\nContent-Signal: " + cs + "\n"
+	}
+	for _, sitemap := range t.Sitemaps {
+		if strings.ContainsAny(sitemap, "\r\n") {
+			return "", fmt.Errorf("robotstxt: invalid sitemap %q", sitemap)
+		}
+		body += "\nSitemap: " + sitemap
+	}
+	if len(t.Sitemaps) > 0 {
+		body += "\n"
+	}
+	return body, nil
+}
+
+// renderAgentSignals adds the per-agent Content-Signal lines to body as
+// rendered by renderRules from rules, in agent order. A signal goes right
+// after the agent's first user-agent line, as the matcher uses a group's
+// first one. Agents without a group get the "*" rules, or "Disallow:" to
+// close the group if there are none.
+func renderAgentSignals(body string, rules []Rule, signals map[string]*ContentSignal) (string, error) {
+	agents := make([]string, 0, len(signals))
+	for agent := range signals {
+		agents = append(agents, agent)
+	}
+	sort.Strings(agents)
+	for _, agent := range agents {
+		if agent == "" || agent == "*" || strings.ContainsAny(agent, "\r\n#") {
+			return "", fmt.Errorf("robotstxt: invalid content signal user-agent %q", agent)
+		}
+		cs := renderContentSignal(signals[agent])
+		if cs == "" {
+			continue
+		}
+		header := "User-agent: " + agent + "\n"
+		i := 0
+		if !strings.HasPrefix(body, header) {
+			if i = strings.Index(body, "\n"+header); i >= 0 {
+				i++
+			}
+		}
+		if i < 0 {
+			if body != "" {
+				body += "\n"
+			}
+			group := ""
+			for _, r := range rules {
+				switch {
+				case r.UserAgent != "*":
+				case r.Allow:
+					group += "Allow: " + r.Pattern + "\n"
+				default:
+					group += "Disallow: " + r.Pattern + "\n"
+				}
+			}
+			if group == "" {
+				group = "Disallow:\n"
+			}
+			body += header + "Content-Signal: " + cs + "\n" + group
+			continue
+		}
+		i += len(header)
+		body = body[:i] + "Content-Signal: " + cs + "\n" + body[i:]
+	}
+	return body, nil
+}
+
+// renderContentSignal returns the Content-Signal value for the set fields of
+// cs, or "" if none are set.
+func renderContentSignal(cs *ContentSignal) string {
+	if cs == nil {
+		return ""
+	}
+	var parts []string
+	for _, f := range []struct {
+		key string
+		val TriState
+	}{{"search", cs.Search}, {"ai-input", cs.AIInput}, {"ai-train", cs.AITrain}} {
+		if f.val != Unset {
+			parts = append(parts, f.key+"="+f.val.String())
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// TenantStore serves a generated robots.txt per Host header for multi-tenant
+// platforms. Bodies are rendered once on Set and served with an ETag.
+// The zero value is ready to use and is safe for concurrent use.
+type TenantStore struct {
+	// MaxAge, if positive, is sent as Cache-Control max-age.
+	MaxAge time.Duration
+
+	mu      sync.RWMutex
+	tenants map[string]renderedTenant
+}
+
+type renderedTenant struct {
+	body []byte
+	etag string
+}
+
+// Set registers or replaces the tenant for host.
+func (s *TenantStore) Set(host string, t Tenant) error {
+	body, err := t.Render()
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256([]byte(body))
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tenants == nil {
+		s.tenants = make(map[string]renderedTenant)
+	}
+	s.tenants[tenantKey(host)] = renderedTenant{
+		body: []byte(body),
+		etag: `"` + hex.EncodeToString(sum[:16]) + `"`,
+	}
+	return nil
+}
+
+// Delete removes the tenant for host.
+func (s *TenantStore) Delete(host string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tenants, tenantKey(host))
+}
+
+// ServeHTTP serves the robots.txt of the tenant named by the request's Host
+// header, or 404 for unknown hosts.
+func (s *TenantStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	t, ok := s.tenants[tenantKey(r.Host)]
+	s.mu.RUnlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", "text/plain; charset=utf-8")
+	h.Set("ETag", t.etag)
+	if s.MaxAge > 0 {
+		h.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(s.MaxAge.Seconds())))
+	}
+	if match := r.Header.Get("If-None-Match"); match != "" && strings.Contains(match, t.etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(t.body)
+}
+
+// tenantKey normalizes a host or Host header to a lowercase ASCII hostname.
+func tenantKey(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(host, ".")
+	if h, err := asciiHost(host); err == nil {
+		return h
+	}
+	return strings.ToLower(host)
+}
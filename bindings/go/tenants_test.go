@@ -0,0 +1,113 @@
+package robotstxt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTenantStore(t *testing.T) {
+	s := &TenantStore{MaxAge: time.Hour}
+	err := s.Set("shop.example.com", Tenant{
+		Rules:    []Rule{{UserAgent: "*", Pattern: "/cart/"}},
+		Sitemaps: []string{"https://shop.example.com/sitemap.xml"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/robots.txt", nil)
+	req.Host = "Shop.Example.com:8080"
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+
+	want := "User-agent: *\nDisallow: /cart/\n\nSitemap: https://shop.example.com/sitemap.xml\n"
+	if rec.Code != http.StatusOK || rec.Body.String() != want {
+		t.Fatalf("Got %d %q, want 200 %q", rec.Code, rec.Body.String(), want)
+	}
+	if rec.Header().Get("Cache-Control") != "public, max-age=3600" {
+		t.Errorf("Unexpected Cache-Control %q", rec.Header().Get("Cache-Control"))
+	}
+
+	etag := rec.Header().Get("ETag")
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 for matching ETag, got %d", rec.Code)
+	}
+
+	s.Delete("shop.example.com")
+	rec = httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 after Delete, got %d", rec.Code)
+	}
+}
+
+func TestTenantStoreInvalid(t *testing.T) {
+	var s TenantStore
+	if err := s.Set("example.com", Tenant{Sitemaps: []string{"a\nDisallow: /"}}); err == nil {
+		t.Error("Expected error for sitemap with newline")
+	}
+}
+
+func TestTenantStoreIDN(t *testing.T) {
+	var s TenantStore
+	if err := s.Set("münchen.example", Tenant{Rules: []Rule{{UserAgent: "*", Pattern: "/"}}}); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/robots.txt", nil)
+	req.Host = "xn--mnchen-3ya.example"
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected punycode Host to find IDN tenant, got %d", rec.Code)
+	}
+}
+
+func TestTenantAgentSignals(t *testing.T) {
+	tenant := Tenant{
+		Rules: []Rule{{UserAgent: "*", Pattern: "/cart/"}, {UserAgent: "GPTBot", Pattern: "/private/"}},
+		AgentSignals: map[string]*ContentSignal{
+			"GPTBot": {AITrain: No},
+			"CCBot":  {AITrain: No, AIInput: No},
+		},
+	}
+	body, err := tenant.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "User-agent: *\nDisallow: /cart/\n\nUser-agent: GPTBot\nContent-Signal: ai-train=no\nDisallow: /private/\n\n" +
+		"User-agent: CCBot\nContent-Signal: ai-input=no, ai-train=no\nDisallow: /cart/\n"
+	if body != want {
+		t.Fatalf("Render = %q, want %q", body, want)
+	}
+
+	if ContentSignalSupported() {
+		m := NewMatcher()
+		defer m.Free()
+		for _, agent := range []string{"GPTBot", "CCBot"} {
+			r := m.Check(body, agent, "https://example.com/")
+			if r.ContentSignal == nil || r.ContentSignal.AITrain != No {
+				t.Errorf("%s: ContentSignal = %+v, want ai-train=no", agent, r.ContentSignal)
+			}
+		}
+		if m.IsAllowed(body, "CCBot", "https://example.com/cart/") {
+			t.Error("CCBot lost the * rules to its signal group")
+		}
+	}
+
+	body, err = Tenant{AgentSignals: map[string]*ContentSignal{"CCBot": {AITrain: No}}}.Render()
+	if err != nil || body != "User-agent: CCBot\nContent-Signal: ai-train=no\nDisallow:\n" {
+		t.Errorf("Render without rules = %q, %v", body, err)
+	}
+
+	for _, agent := range []string{"*", "", "a\nb"} {
+		tenant.AgentSignals = map[string]*ContentSignal{agent: {AITrain: No}}
+		if _, err := tenant.Render(); err == nil {
+			t.Errorf("Render with signal for %q succeeded, want error", agent)
+		}
+	}
+}
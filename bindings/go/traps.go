@@ -0,0 +1,77 @@
+package robotstxt
+
+import (
+	"regexp"
+	"strings"
+)
+
+// TrapKind classifies a likely crawler trap.
+type TrapKind string
+
+const (
+	// TrapCalendar is a disallowed date-driven path that usually generates
+	// an unbounded number of pages (calendars, archives by day).
+	TrapCalendar TrapKind = "calendar"
+	// TrapFacet is a disallowed sort/filter parameter, hinting at a
+	// combinatorial faceted-navigation space.
+	TrapFacet TrapKind = "facet"
+	// TrapSessionID is a session identifier in an allow rule, which makes
+	// every visit produce new URLs.
+	TrapSessionID TrapKind = "session-id"
+	// TrapWildcard is an extremely long or wildcard-heavy rule, often
+	// machine-generated and costly to match.
+	TrapWildcard TrapKind = "wildcard"
+)
+
+// TrapFinding is an advisory hint about a rule that suggests a crawler trap.
+type TrapFinding struct {
+	Line    int
+	Kind    TrapKind
+	Pattern string
+	Message string
+}
+
+// Thresholds for TrapWildcard.
+const (
+	trapMaxPatternLen = 256
+	trapMaxWildcards  = 6
+)
+
+var (
+	trapDatePath   = regexp.MustCompile(`/(19|20)\d\d[/-](0?[1-9]|1[0-2])([/-]|$)`)
+	trapDateParam  = regexp.MustCompile(`[?&](date|day|month|year|week|cal(endar)?_?date)=`)
+	trapFacetParam = regexp.MustCompile(`[?&*](sort|order|orderby|filter|facet|view|limit|per_page|price)=`)
+	trapSessionID  = regexp.MustCompile(`(jsessionid|phpsessid|aspsessionid|sessionid|session_id|[?&;]sid=|[?&]s=[0-9a-f]{16,})`)
+)
+
+// DetectTraps flags rules in p that hint at crawler traps: disallowed
+// calendar or faceted-navigation paths, session IDs in allow rules, and
+// extremely long or wildcard-heavy patterns. Findings are heuristics for
+// tuning frontier filters, not errors.
+func DetectTraps(p *ParsedRobots) []TrapFinding {
+	var out []TrapFinding
+	add := func(d Directive, kind TrapKind, msg string) {
+		out = append(out, TrapFinding{Line: d.Line, Kind: kind, Pattern: d.Value, Message: msg})
+	}
+	for _, d := range p.Directives {
+		if d.Type != DirectiveAllow && d.Type != DirectiveDisallow {
+			continue
+		}
+		v := strings.ToLower(d.Value)
+		if d.Type == DirectiveDisallow {
+			if strings.Contains(v, "calendar") || trapDatePath.MatchString(v) || trapDateParam.MatchString(v) {
+				add(d, TrapCalendar, "date-driven path; likely an infinite calendar or archive")
+			}
+			if trapFacetParam.MatchString(v) {
+				add(d, TrapFacet, "sort/filter parameter; likely a faceted-navigation space")
+			}
+		}
+		if d.Type == DirectiveAllow && trapSessionID.MatchString(v) {
+			add(d, TrapSessionID, "session identifier in an allowed URL; every visit yields new URLs")
+		}
+		if len(d.Value) > trapMaxPatternLen || strings.Count(d.Value, "*") > trapMaxWildcards {
+			add(d, TrapWildcard, "extremely long or wildcard-heavy pattern")
+		}
+	}
+	return out
+}
@@ -0,0 +1,39 @@
+package robotstxt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectTraps(t *testing.T) {
+	robotsTxt := "User-agent: *\n" +
+		"Disallow: /events/calendar/\n" +
+		"Disallow: /archive/2023/07/\n" +
+		"Disallow: /*?sort=\n" +
+		"Allow: /shop;jsessionid=\n" +
+		"Disallow: /" + strings.Repeat("a*", 8) + "\n" +
+		"Disallow: /private/\n" +
+		"Allow: /calendar-of-events\n"
+	got := DetectTraps(Parse(robotsTxt))
+	want := []struct {
+		line int
+		kind TrapKind
+	}{
+		{2, TrapCalendar},
+		{3, TrapCalendar},
+		{4, TrapFacet},
+		{5, TrapSessionID},
+		{6, TrapWildcard},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("DetectTraps = %+v, want %d findings", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].Line != w.line || got[i].Kind != w.kind || got[i].Message == "" {
+			t.Errorf("Finding %d = %+v, want line %d kind %s", i, got[i], w.line, w.kind)
+		}
+	}
+	if f := DetectTraps(Parse("User-agent: *\nDisallow: /admin/\n")); len(f) != 0 {
+		t.Errorf("Expected no findings, got %+v", f)
+	}
+}
@@ -0,0 +1,143 @@
+package robotstxt
+
+import (
+	"strings"
+)
+
+// TreeVerdict summarizes the verdicts of the URLs under a directory.
+type TreeVerdict int
+
+const (
+	// AllAllowed means every URL considered is allowed.
+	AllAllowed TreeVerdict = iota
+	// AllDisallowed means every URL considered is disallowed.
+	AllDisallowed
+	// Mixed means some are allowed and some are not.
+	Mixed
+)
+
+// String returns "allowed", "disallowed" or "mixed".
+func (v TreeVerdict) String() string {
+	switch v {
+	case AllAllowed:
+		return "allowed"
+	case AllDisallowed:
+		return "disallowed"
+	}
+	return "mixed"
+}
+
+// SummarizeTree checks each of paths, absolute URLs or paths starting with
+// "/", for agent and reports per directory whether the sampled URLs under
+// it are all allowed, all disallowed or mixed. Every directory containing a
+// sample is included, up to "/", keyed by its path with a trailing slash,
+// for tree views of crawlability. The verdicts only cover the sample.
+func (m *Matcher) SummarizeTree(robotsTxt, agent string, paths []string) map[string]TreeVerdict {
+	out := make(map[string]TreeVerdict)
+	for _, p := range paths {
+		v := AllDisallowed
+		if m.IsAllowed(robotsTxt, agent, p) {
+			v = AllAllowed
+		}
+		dir := urlPath(p)
+		dir = dir[:strings.LastIndexByte(dir, '/')+1]
+		for i := 0; i < len(dir); i++ {
+			if dir[i] != '/' {
+				continue
+			}
+			d := dir[:i+1]
+			if prev, ok := out[d]; ok && prev != v {
+				out[d] = Mixed
+			} else if !ok {
+				out[d] = v
+			}
+		}
+	}
+	return out
+}
+
+// IsTreeAllowed reports whether every URL under urlPrefix, an absolute URL
+// or a path, shares a verdict for agent in p, so frontier schedulers can
+// prune whole subtrees. A rule either matches the entire subtree, matches
+// none of it, or may match part of it; Mixed is reported whenever partial
+// matches could change a verdict, including undecidable wildcard cases, so
+// AllAllowed and AllDisallowed are always safe to act on.
+func IsTreeAllowed(p *ParsedRobots, agent, urlPrefix string) TreeVerdict {
+	prefix := urlPathQuery(urlPrefix)
+	var allowed, disallowed bool
+	covered := false
+	for _, r := range EffectiveRules(p, agent) {
+		all, some := treeMatch(r.Pattern, prefix)
+		if !all && !some {
+			continue
+		}
+		if r.Allow {
+			allowed = true
+		} else {
+			disallowed = true
+		}
+		if all {
+			covered = true
+			break
+		}
+	}
+	if !covered {
+		allowed = true
+	}
+	switch {
+	case allowed && disallowed:
+		return Mixed
+	case disallowed:
+		return AllDisallowed
+	}
+	return AllAllowed
+}
+
+// treeMatch reports whether pattern matches every URL starting with prefix
+// (all), or may match some of them (some). Anything after a wildcard is
+// treated as able to match or not.
+func treeMatch(pattern, prefix string) (all, some bool) {
+	literal := strings.TrimRight(pattern, "*")
+	if !strings.ContainsAny(literal, "*$") {
+		if strings.HasPrefix(prefix, literal) {
+			return true, true
+		}
+		return false, strings.HasPrefix(literal, prefix)
+	}
+	if i := strings.IndexByte(pattern, '*'); i >= 0 {
+		literal = pattern[:i]
+	} else {
+		literal = strings.TrimSuffix(pattern, "$")
+	}
+	return false, strings.HasPrefix(prefix, literal) || strings.HasPrefix(literal, prefix)
+}
+
+// urlPathQuery returns the path and query of an absolute URL or a path,
+// without the fragment.
+func urlPathQuery(s string) string {
+	if i := strings.Index(s, "://"); i >= 0 {
+		s = s[i+3:]
+		if j := strings.IndexAny(s, "/?#"); j >= 0 {
+			s = s[j:]
+		} else {
+			s = ""
+		}
+	}
+	if i := strings.IndexByte(s, '#'); i >= 0 {
+		s = s[:i]
+	}
+	if !strings.HasPrefix(s, "/") {
+		return "/" + s
+	}
+	return s
+}
+
+// urlPath returns the path of an absolute URL or a path, without query or
+// fragment, and "/" if it has none.
+func urlPath(s string) string {
+	s = urlPathQuery(s)
+	if i := strings.IndexByte(s, '?'); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
@@ -0,0 +1,92 @@
+package robotstxt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSummarizeTree(t *testing.T) {
+	m := NewMatcher()
+	defer m.Free()
+	robotsTxt := "User-agent: *\nDisallow: /private/\nAllow: /private/press/\nDisallow: /*.pdf$\n"
+	got := m.SummarizeTree(robotsTxt, "FooBot", []string{
+		"/index.html",
+		"/docs/a.html",
+		"/docs/b.pdf",
+		"https://example.com/private/x",
+		"https://example.com/private/y?z=1",
+		"/private/press/release",
+		"/tmp/a/b",
+		"https://example.com",
+	})
+	want := map[string]TreeVerdict{
+		"/":               Mixed,
+		"/docs/":          Mixed,
+		"/private/":       Mixed,
+		"/private/press/": AllAllowed,
+		"/tmp/":           AllAllowed,
+		"/tmp/a/":         AllAllowed,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SummarizeTree = %v, want %v", got, want)
+	}
+	got = m.SummarizeTree(robotsTxt, "FooBot", []string{"/private/a", "/private/b/c"})
+	if got["/private/"] != AllDisallowed || got["/private/b/"] != AllDisallowed || got["/"] != AllDisallowed {
+		t.Errorf("SummarizeTree of blocked sample = %v", got)
+	}
+	if s := Mixed.String(); s != "mixed" {
+		t.Errorf("Mixed.String() = %q", s)
+	}
+}
+
+func TestIsTreeAllowed(t *testing.T) {
+	robotsTxt := `User-agent: *
+Disallow: /private/
+Allow: /private/press
+Disallow: /*.pdf$
+Disallow: /search$
+Disallow: /a*b
+
+User-agent: Googlebot
+Disallow: /
+Allow: /public/
+`
+	p := Parse(robotsTxt)
+	tests := []struct {
+		agent, prefix string
+		want          TreeVerdict
+	}{
+		{"FooBot", "/private/press/", AllAllowed},
+		{"FooBot", "https://example.com/private/", Mixed},
+		{"FooBot", "/private/x/", AllDisallowed},
+		{"FooBot", "/search", Mixed},
+		{"FooBot", "/a", Mixed},
+		{"Googlebot", "/", Mixed},
+		{"Googlebot", "/public/docs/", AllAllowed},
+		{"Googlebot", "https://example.com/admin/", AllDisallowed},
+		{"Googlebot", "/publication", AllDisallowed},
+		{"FooBot", "/private/press?id=1", AllAllowed},
+	}
+	for _, tt := range tests {
+		if got := IsTreeAllowed(p, tt.agent, tt.prefix); got != tt.want {
+			t.Errorf("IsTreeAllowed(%s, %s) = %v, want %v", tt.agent, tt.prefix, got, tt.want)
+		}
+	}
+
+	// A uniform verdict must hold for every URL under the prefix.
+	m := NewMatcher()
+	defer m.Free()
+	for _, tt := range tests {
+		v := IsTreeAllowed(p, tt.agent, tt.prefix)
+		if v == Mixed {
+			continue
+		}
+		prefix := urlPathQuery(tt.prefix)
+		for _, suffix := range []string{"", "x", "x.pdf", "/b", "?q=1", "press/a.pdf"} {
+			url := "https://example.com" + prefix + suffix
+			if allowed := m.IsAllowed(robotsTxt, tt.agent, url); allowed != (v == AllAllowed) {
+				t.Errorf("%s %s: IsTreeAllowed = %v but IsAllowed = %v", tt.agent, url, v, allowed)
+			}
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package robotstxt
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// ErrInvalidURL is wrapped by errors for URLs that are malformed or not
+// absolute.
+var ErrInvalidURL = errors.New("robotstxt: invalid URL")
+
+// ValidateURL reports whether rawURL is an absolute URL with a well-formed
+// host: bracketed IPv6 literals must parse and ports must be in range.
+// Userinfo is permitted.
+func ValidateURL(rawURL string) error {
+	_, err := parseAbsoluteURL(rawURL)
+	return err
+}
+
+// parseAbsoluteURL parses and validates rawURL; see ValidateURL.
+func parseAbsoluteURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return nil, fmt.Errorf("%w %q: %v", ErrInvalidURL, rawURL, err)
+	}
+	if u.Scheme == "" || u.Host == "" || u.Opaque != "" {
+		return nil, fmt.Errorf("%w %q: not an absolute URL", ErrInvalidURL, rawURL)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return nil, fmt.Errorf("%w %q: empty host", ErrInvalidURL, rawURL)
+	}
+	if strings.HasPrefix(u.Host, "[") {
+		if i := strings.IndexByte(host, '%'); i >= 0 {
+			host = host[:i]
+		}
+		if !strings.Contains(host, ":") || net.ParseIP(host) == nil {
+			return nil, fmt.Errorf("%w %q: bad IPv6 literal", ErrInvalidURL, rawURL)
+		}
+	} else if strings.Count(u.Host, ":") > 1 {
+		return nil, fmt.Errorf("%w %q: unbracketed IPv6 literal", ErrInvalidURL, rawURL)
+	}
+	if port := u.Port(); port != "" {
+		if n, err := strconv.Atoi(port); err != nil || n > 65535 {
+			return nil, fmt.Errorf("%w %q: bad port", ErrInvalidURL, rawURL)
+		}
+	}
+	return u, nil
+}
+
+// matchTarget returns the form of rawURL handed to the native matcher:
+// userinfo and fragment are dropped so that neither can be mistaken for
+// part of the path.
+func matchTarget(rawURL string) (string, error) {
+	u, err := parseAbsoluteURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+	return u.Scheme + "://" + u.Host + u.RequestURI(), nil
+}
+
+// Origin returns the scheme://host[:port] origin of rawURL in canonical
+// form: lowercase scheme, ASCII (punycode) host, default port omitted. A
+// robots.txt file only applies to URLs with the same origin.
+func Origin(rawURL string) (string, error) {
+	u, err := parseAbsoluteURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+	scheme := strings.ToLower(u.Scheme)
+	host, err := asciiHost(u.Hostname())
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port := u.Port(); port != "" && port != defaultPort(scheme) {
+		host += ":" + port
+	}
+	return scheme + "://" + host, nil
+}
+
+// NormalizeURL returns rawURL with its scheme lowercased and its host in
+// canonical ASCII form, so that internationalized and punycode spellings of
+// the same URL yield the same cache key.
+func NormalizeURL(rawURL string) (string, error) {
+	u, err := parseAbsoluteURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+	host, err := asciiHost(u.Hostname())
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port := u.Port(); port != "" {
+		host += ":" + port
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = host
+	return u.String(), nil
+}
+
+// asciiHost returns host in lowercase ASCII form, converting
+// internationalized names to punycode with IDNA2008 (UTS #46) mapping:
+// "münchen.example" becomes "xn--mnchen-3ya.example".
+func asciiHost(host string) (string, error) {
+	for i := 0; i < len(host); i++ {
+		if host[i] >= 0x80 {
+			h, err := idna.Lookup.ToASCII(host)
+			if err != nil {
+				return "", fmt.Errorf("robotstxt: invalid host %q: %v", host, err)
+			}
+			return h, nil
+		}
+	}
+	return strings.ToLower(host), nil
+}
+
+// defaultPort returns the well-known port for scheme, or "" if unknown.
+func defaultPort(scheme string) string {
+	switch scheme {
+	case "http":
+		return "80"
+	case "https":
+		return "443"
+	case "ftp":
+		return "21"
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package robotstxt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// VerifyReport is the result of Verify: how a live robots.txt treats one
+// agent.
+type VerifyReport struct {
+	// Origin is the origin whose robots.txt was checked, and StatusCode
+	// the status it was served with.
+	Origin     string
+	StatusCode int
+	// Named reports whether a group names the agent specifically rather than
+	// only through "*".
+	Named bool
+	// Blocked and Allowed partition the sample URLs, as absolute URLs.
+	Blocked []string
+	Allowed []string
+	// CrawlDelay is the crawl-delay in seconds that applies to the agent,
+	// or nil if there is none.
+	CrawlDelay *float64
+}
+
+// Verify fetches origin's robots.txt with a zero Fetcher and reports how it
+// treats myAgent; see Fetcher.Verify.
+func Verify(ctx context.Context, origin, myAgent string, sampleURLs []string) (*VerifyReport, error) {
+	var f Fetcher
+	return f.Verify(ctx, origin, myAgent, sampleURLs)
+}
+
+// Verify fetches origin's live robots.txt and reports whether myAgent is
+// named, which sample URLs are blocked and the applicable crawl-delay. Sample
+// URLs may be paths, which are resolved against origin; absolute URLs must
+// share its origin. Status codes follow RFC 9309: 4xx allows everything and
+// 5xx blocks everything.
+func (f *Fetcher) Verify(ctx context.Context, origin, myAgent string, sampleURLs []string) (*VerifyReport, error) {
+	base, err := Origin(origin)
+	if err != nil {
+		return nil, err
+	}
+	urls := make([]string, len(sampleURLs))
+	for i, u := range sampleURLs {
+		if strings.HasPrefix(u, "/") {
+			u = base + u
+		}
+		if o, err := Origin(u); err != nil {
+			return nil, err
+		} else if o != base {
+			return nil, fmt.Errorf("robotstxt: sample URL %q is not on %s", u, base)
+		}
+		urls[i] = u
+	}
+
+	res, err := f.Fetch(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+	report := &VerifyReport{Origin: base, StatusCode: res.StatusCode}
+	var body string
+	switch {
+	case res.StatusCode >= 500:
+		report.Blocked = urls
+		return report, nil
+	case res.StatusCode >= 400:
+		// Unavailable: no restrictions, so the empty body.
+	case res.StatusCode >= http.StatusOK && res.StatusCode < 300:
+		body = string(res.Body)
+	default:
+		return nil, fmt.Errorf("robotstxt: unexpected status %d for %s", res.StatusCode, res.URL)
+	}
+
+	m := NewMatcher()
+	defer m.Free()
+	for _, u := range urls {
+		if m.IsAllowed(body, myAgent, u) {
+			report.Allowed = append(report.Allowed, u)
+		} else {
+			report.Blocked = append(report.Blocked, u)
+		}
+	}
+	r := m.Check(body, myAgent, base+"/")
+	report.Named = r.EverSeenSpecificAgent
+	report.CrawlDelay = r.CrawlDelay
+	return report, nil
+}
@@ -0,0 +1,52 @@
+package robotstxt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	robots := "User-agent: *\nDisallow: /\n\nUser-agent: MyBot\nDisallow: /private/\nCrawl-delay: 3\n"
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(robots))
+	}))
+	defer srv.Close()
+
+	samples := []string{"/", "/private/x", srv.URL + "/public"}
+	rep, err := Verify(context.Background(), srv.URL, "MyBot", samples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rep.Named || rep.CrawlDelay == nil || *rep.CrawlDelay != 3 {
+		t.Errorf("Unexpected report %+v", rep)
+	}
+	if want := []string{srv.URL + "/private/x"}; !reflect.DeepEqual(rep.Blocked, want) {
+		t.Errorf("Blocked = %v, want %v", rep.Blocked, want)
+	}
+	if len(rep.Allowed) != 2 {
+		t.Errorf("Allowed = %v", rep.Allowed)
+	}
+
+	rep, _ = Verify(context.Background(), srv.URL, "OtherBot", samples)
+	if rep.Named || len(rep.Blocked) != 3 {
+		t.Errorf("Expected OtherBot to be blocked by *, got %+v", rep)
+	}
+
+	status = http.StatusNotFound
+	if rep, _ = Verify(context.Background(), srv.URL, "OtherBot", samples); len(rep.Allowed) != 3 {
+		t.Errorf("Expected 404 to allow everything, got %+v", rep)
+	}
+	status = http.StatusServiceUnavailable
+	if rep, _ = Verify(context.Background(), srv.URL, "MyBot", samples); len(rep.Blocked) != 3 {
+		t.Errorf("Expected 503 to block everything, got %+v", rep)
+	}
+
+	if _, err := Verify(context.Background(), srv.URL, "MyBot", []string{"https://elsewhere.example/"}); err == nil {
+		t.Error("Expected error for sample URL on another origin")
+	}
+}
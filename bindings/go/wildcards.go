@@ -0,0 +1,89 @@
+package robotstxt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WildcardLimit bounds the wildcard structure of allow and disallow
+// patterns. The matcher compares every pattern byte after the first "*"
+// against every candidate position in the path, so its work per URL grows
+// with that span times the path length; hostile files use many wildcards
+// and long tails to make each check expensive. Zero fields mean no limit.
+type WildcardLimit struct {
+	// MaxWildcards is the most "*" a pattern may contain.
+	MaxWildcards int
+	// MaxSpan is the most pattern bytes after the first "*".
+	MaxSpan int
+}
+
+// DefaultWildcardLimit is far above what hand-written files use.
+var DefaultWildcardLimit = WildcardLimit{MaxWildcards: 16, MaxSpan: 512}
+
+// WildcardFinding is a pattern over a WildcardLimit.
+type WildcardFinding struct {
+	Line      int
+	Pattern   string
+	Wildcards int
+	Span      int
+	Message   string
+}
+
+// wildcardSpan returns the number of bytes from the first "*" of pattern
+// to its end, or 0 if it has none.
+func wildcardSpan(pattern string) int {
+	if i := strings.IndexByte(pattern, '*'); i >= 0 {
+		return len(pattern) - i
+	}
+	return 0
+}
+
+// CheckWildcards returns the allow and disallow patterns of robotsTxt that
+// exceed limit, in file order.
+func CheckWildcards(robotsTxt string, limit WildcardLimit) []WildcardFinding {
+	var out []WildcardFinding
+	for _, d := range Parse(robotsTxt).Directives {
+		if d.Type != DirectiveAllow && d.Type != DirectiveDisallow {
+			continue
+		}
+		f := WildcardFinding{Line: d.Line, Pattern: d.Value, Wildcards: strings.Count(d.Value, "*"), Span: wildcardSpan(d.Value)}
+		switch {
+		case limit.MaxWildcards > 0 && f.Wildcards > limit.MaxWildcards:
+			f.Message = fmt.Sprintf("%d wildcards, limit %d", f.Wildcards, limit.MaxWildcards)
+		case limit.MaxSpan > 0 && f.Span > limit.MaxSpan:
+			f.Message = fmt.Sprintf("%d bytes after the first wildcard, limit %d", f.Span, limit.MaxSpan)
+		default:
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// SetWildcardLimit makes IsAllowed, IsAllowedMulti and Check ignore allow
+// and disallow rules over limit, as if their patterns were empty, so a
+// hostile robots.txt cannot make every check expensive. Ignoring a rule
+// can change verdicts either way; report what was dropped with
+// CheckWildcards. The zero WildcardLimit, the default, ignores nothing;
+// Reset keeps the limit and Pool.Put restores the default.
+func (m *Matcher) SetWildcardLimit(limit WildcardLimit) {
+	m.wildcardLimit = limit
+}
+
+// rejectedRule replaces rules dropped by a WildcardLimit. It matches
+// nothing but still ends the user-agent lines above it, so groups and line
+// numbers stay intact.
+const rejectedRule = "Disallow:"
+
+// limitWildcards rewrites robotsTxt without the rules over limit.
+func limitWildcards(robotsTxt string, limit WildcardLimit) string {
+	findings := CheckWildcards(robotsTxt, limit)
+	if len(findings) == 0 {
+		return robotsTxt
+	}
+	lines := splitLines(robotsTxt)
+	for _, f := range findings {
+		lines[f.Line-1] = rejectedRule
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,46 @@
+package robotstxt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckWildcards(t *testing.T) {
+	hostile := "/" + strings.Repeat("*a", 40) + "$"
+	robotsTxt := "User-agent: *\nDisallow: /*.pdf$\nDisallow: " + hostile + "\nAllow: /x*" + strings.Repeat("b", 600) + "\n"
+
+	got := CheckWildcards(robotsTxt, DefaultWildcardLimit)
+	if len(got) != 2 {
+		t.Fatalf("CheckWildcards = %+v, want 2 findings", got)
+	}
+	if got[0].Line != 3 || got[0].Wildcards != 40 || got[0].Pattern != hostile || !strings.Contains(got[0].Message, "40 wildcards") {
+		t.Errorf("wildcard finding = %+v", got[0])
+	}
+	if got[1].Line != 4 || got[1].Span != 601 || !strings.Contains(got[1].Message, "after the first wildcard") {
+		t.Errorf("span finding = %+v", got[1])
+	}
+	if f := CheckWildcards(robotsTxt, WildcardLimit{}); len(f) != 0 {
+		t.Errorf("zero limit reported %+v", f)
+	}
+}
+
+func TestSetWildcardLimit(t *testing.T) {
+	m := NewMatcher()
+	defer m.Free()
+	robotsTxt := "User-agent: *\nDisallow: /" + strings.Repeat("*a", 40) + "\nDisallow: /private\n\nUser-agent: FooBot\nDisallow: /foo\n"
+	url := "https://example.com/" + strings.Repeat("a", 50)
+
+	if m.IsAllowed(robotsTxt, "BarBot", url) {
+		t.Fatal("hostile pattern did not match without a limit")
+	}
+	m.SetWildcardLimit(DefaultWildcardLimit)
+	if !m.IsAllowed(robotsTxt, "BarBot", url) {
+		t.Error("hostile pattern still applied under the limit")
+	}
+	if m.IsAllowed(robotsTxt, "BarBot", "https://example.com/private") || m.MatchingLine() != 3 {
+		t.Errorf("other rules or line numbers changed: line %d", m.MatchingLine())
+	}
+	if m.IsAllowed(robotsTxt, "FooBot", "https://example.com/foo") || m.MatchingLine() != 6 {
+		t.Error("dropping a rule changed the groups")
+	}
+}
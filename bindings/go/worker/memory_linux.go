@@ -0,0 +1,48 @@
+//go:build linux
+
+package worker
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// memorySampleInterval is how often a busy worker's memory is sampled.
+const memorySampleInterval = 5 * time.Millisecond
+
+// watchMemory returns a channel that is closed once proc's resident memory
+// exceeds limit, sampling until stop is closed. It returns nil if limit is
+// not positive.
+func watchMemory(proc *process, limit int64, stop <-chan struct{}) <-chan struct{} {
+	if limit <= 0 {
+		return nil
+	}
+	exceeded := make(chan struct{})
+	path := fmt.Sprintf("/proc/%d/statm", proc.cmd.Process.Pid)
+	pageSize := int64(os.Getpagesize())
+	go func() {
+		ticker := time.NewTicker(memorySampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return
+			}
+			var size, resident int64
+			if _, err := fmt.Sscan(string(data), &size, &resident); err != nil {
+				return
+			}
+			if resident*pageSize > limit {
+				close(exceeded)
+				return
+			}
+		}
+	}()
+	return exceeded
+}
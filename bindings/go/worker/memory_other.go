@@ -0,0 +1,8 @@
+//go:build !linux
+
+package worker
+
+// watchMemory is not supported outside Linux; MaxMemory is ignored.
+func watchMemory(proc *process, limit int64, stop <-chan struct{}) <-chan struct{} {
+	return nil
+}
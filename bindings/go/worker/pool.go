@@ -0,0 +1,260 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"time"
+
+	robotstxt "github.com/nzrsky/robotstxt/bindings/go"
+)
+
+// ErrClosed is returned by a Pool after Close.
+var ErrClosed = errors.New("worker: pool closed")
+
+// ErrResourceLimit is returned when a check exceeds one of the pool's Limits.
+var ErrResourceLimit = errors.New("worker: resource limit exceeded")
+
+// ErrTimeout is returned when a check runs past Limits.MaxDuration or the
+// timeout given to IsAllowedTimeout. It also matches ErrResourceLimit.
+var ErrTimeout error = limitError("worker: check timed out")
+
+// limitError is a resource-limit error of its own kind.
+type limitError string
+
+func (e limitError) Error() string        { return string(e) }
+func (e limitError) Is(target error) bool { return target == ErrResourceLimit }
+
+// Limits bounds the resources of a single check. Zero fields are unlimited.
+type Limits struct {
+	// MaxInputSize caps the robots.txt body in bytes. Larger inputs are
+	// rejected without reaching a worker.
+	MaxInputSize int
+	// MaxDuration caps the wall-clock time of one check. Workers handle one
+	// check at a time, so this also bounds its CPU time.
+	MaxDuration time.Duration
+	// MaxMemory caps the resident memory of a worker in bytes. It is
+	// enforced by sampling and only on Linux.
+	MaxMemory int64
+}
+
+// Config describes the worker processes of a Pool.
+type Config struct {
+	// Path and Args name the worker executable, e.g. the robots-worker
+	// command.
+	Path string
+	Args []string
+	// Env is the worker environment; the current environment if nil.
+	Env []string
+	// Size is the maximum number of worker processes; runtime.NumCPU() if
+	// zero.
+	Size int
+	// Limits bounds each check. A worker exceeding them is killed.
+	Limits Limits
+}
+
+// Pool dispatches checks to worker processes, starting them on demand and
+// replacing any that crash. A Pool is safe for concurrent use.
+type Pool struct {
+	cfg   Config
+	slots chan struct{}
+	idle  chan *process
+
+	mu     sync.Mutex
+	closed bool
+}
+
+type process struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	enc   *json.Encoder
+	dec   *json.Decoder
+}
+
+// NewPool creates a pool of worker processes described by cfg.
+func NewPool(cfg Config) *Pool {
+	if cfg.Size <= 0 {
+		cfg.Size = runtime.NumCPU()
+	}
+	return &Pool{
+		cfg:   cfg,
+		slots: make(chan struct{}, cfg.Size),
+		idle:  make(chan *process, cfg.Size),
+	}
+}
+
+// IsAllowed checks url for userAgent against robotsTxt in a worker process.
+// If the worker dies, the error wraps ErrCrashed; if the check exceeds the
+// pool's Limits, it wraps ErrResourceLimit, and ErrTimeout for MaxDuration.
+// Both are *robotstxt.BackendErrors.
+// If ctx is done first, the worker is killed and ctx.Err() is returned.
+func (p *Pool) IsAllowed(ctx context.Context, robotsTxt, userAgent, url string) (Result, error) {
+	return p.check(ctx, p.cfg.Limits, robotsTxt, userAgent, url)
+}
+
+// IsAllowedTimeout is IsAllowed with the wall-clock time of this check
+// bounded by timeout as well as by MaxDuration. A check running past it
+// kills its worker, so one adversarial document cannot exceed the caller's
+// latency budget, and returns an error wrapping ErrTimeout.
+func (p *Pool) IsAllowedTimeout(ctx context.Context, timeout time.Duration, robotsTxt, userAgent, url string) (Result, error) {
+	limits := p.cfg.Limits
+	if timeout > 0 && (limits.MaxDuration <= 0 || timeout < limits.MaxDuration) {
+		limits.MaxDuration = timeout
+	}
+	return p.check(ctx, limits, robotsTxt, userAgent, url)
+}
+
+// backendError classifies err as a worker failure.
+func backendError(err error) error {
+	return &robotstxt.BackendError{Backend: "worker", Err: err}
+}
+
+func (p *Pool) check(ctx context.Context, limits Limits, robotsTxt, userAgent, url string) (Result, error) {
+	if limits.MaxInputSize > 0 && len(robotsTxt) > limits.MaxInputSize {
+		return Result{}, backendError(fmt.Errorf("%w: input of %d bytes exceeds %d", ErrResourceLimit, len(robotsTxt), limits.MaxInputSize))
+	}
+
+	proc, err := p.get(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+
+	type reply struct {
+		res Result
+		err error
+	}
+	done := make(chan reply, 1)
+	go func() {
+		var r reply
+		if r.err = proc.enc.Encode(request{Robots: []byte(robotsTxt), UserAgent: []byte(userAgent), URL: []byte(url)}); r.err == nil {
+			r.err = proc.dec.Decode(&r.res)
+		}
+		done <- r
+	}()
+
+	var timeout <-chan time.Time
+	if limits.MaxDuration > 0 {
+		timer := time.NewTimer(limits.MaxDuration)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	overMemory := watchMemory(proc, limits.MaxMemory, stop)
+
+	select {
+	case r := <-done:
+		if r.err != nil {
+			p.discard(proc)
+			return Result{}, backendError(fmt.Errorf("%w: %v", ErrCrashed, r.err))
+		}
+		p.put(proc)
+		return r.res, nil
+	case <-ctx.Done():
+		p.discard(proc)
+		return Result{}, ctx.Err()
+	case <-timeout:
+		p.discard(proc)
+		return Result{}, backendError(fmt.Errorf("%w after %v", ErrTimeout, limits.MaxDuration))
+	case <-overMemory:
+		p.discard(proc)
+		return Result{}, backendError(fmt.Errorf("%w: worker exceeded %d bytes", ErrResourceLimit, limits.MaxMemory))
+	}
+}
+
+// Close stops all idle workers; busy workers stop when their check ends.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+	for {
+		select {
+		case proc := <-p.idle:
+			p.discard(proc)
+		default:
+			return nil
+		}
+	}
+}
+
+func (p *Pool) isClosed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed
+}
+
+// get returns an idle worker or starts a new one if below Size.
+func (p *Pool) get(ctx context.Context) (*process, error) {
+	if p.isClosed() {
+		return nil, ErrClosed
+	}
+	select {
+	case proc := <-p.idle:
+		return proc, nil
+	default:
+	}
+	select {
+	case proc := <-p.idle:
+		return proc, nil
+	case p.slots <- struct{}{}:
+		proc, err := p.start()
+		if err != nil {
+			<-p.slots
+			return nil, err
+		}
+		return proc, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// put returns proc to the idle workers, or discards it after Close. The
+// send happens under mu so Close cannot drain idle between the check and
+// the send; it never blocks, since idle holds a process per slot.
+func (p *Pool) put(proc *process) {
+	p.mu.Lock()
+	if !p.closed {
+		p.idle <- proc
+		p.mu.Unlock()
+		return
+	}
+	p.mu.Unlock()
+	p.discard(proc)
+}
+
+// discard kills proc and frees its slot.
+func (p *Pool) discard(proc *process) {
+	proc.stdin.Close()
+	proc.cmd.Process.Kill()
+	proc.cmd.Wait()
+	<-p.slots
+}
+
+func (p *Pool) start() (*process, error) {
+	cmd := exec.Command(p.cfg.Path, p.cfg.Args...)
+	cmd.Env = p.cfg.Env
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &process{
+		cmd:   cmd,
+		stdin: stdin,
+		enc:   json.NewEncoder(stdin),
+		dec:   json.NewDecoder(stdout),
+	}, nil
+}
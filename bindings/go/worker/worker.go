@@ -0,0 +1,71 @@
+// Package worker runs robots.txt matching in child processes, so that a
+// crash in the native parser on adversarial input kills one worker instead
+// of the whole crawler.
+//
+// The worker executable calls Serve; the robots-worker command does exactly
+// that. The parent checks URLs through a Pool:
+//
+//	pool := worker.NewPool(worker.Config{Path: "robots-worker"})
+//	defer pool.Close()
+//
+//	res, err := pool.IsAllowed(ctx, robotsTxt, "Googlebot", url)
+//	if errors.Is(err, worker.ErrCrashed) {
+//		// The worker died on this input; it has been replaced.
+//	}
+package worker
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+
+	robotstxt "github.com/nzrsky/robotstxt/bindings/go"
+)
+
+// ErrCrashed is returned when a worker process dies or breaks the protocol
+// while handling a request.
+var ErrCrashed = errors.New("worker: process crashed")
+
+// request is one check sent to a worker, encoded as a JSON line. The fields
+// are bytes, base64 on the wire, because JSON strings would replace invalid
+// UTF-8 and change the verdict.
+type request struct {
+	Robots    []byte `json:"robots"`
+	UserAgent []byte `json:"user_agent"`
+	URL       []byte `json:"url"`
+}
+
+// Result is the outcome of a check performed by a worker.
+type Result struct {
+	Allowed      bool     `json:"allowed"`
+	MatchingLine int      `json:"matching_line"`
+	CrawlDelay   *float64 `json:"crawl_delay,omitempty"`
+}
+
+// Serve answers requests read from r on w until r is exhausted. It is the
+// body of a worker process, called with os.Stdin and os.Stdout.
+func Serve(r io.Reader, w io.Writer) error {
+	m := robotstxt.NewMatcher()
+	defer m.Free()
+
+	dec := json.NewDecoder(bufio.NewReader(r))
+	enc := json.NewEncoder(w)
+	for {
+		var req request
+		if err := dec.Decode(&req); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		res := Result{
+			Allowed:      m.IsAllowed(string(req.Robots), string(req.UserAgent), string(req.URL)),
+			MatchingLine: m.MatchingLine(),
+			CrawlDelay:   m.CrawlDelay(),
+		}
+		if err := enc.Encode(res); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	robotstxt "github.com/nzrsky/robotstxt/bindings/go"
+)
+
+// TestMain lets the test binary act as its own worker process.
+func TestMain(m *testing.M) {
+	switch os.Getenv("ROBOTSTXT_TEST_WORKER") {
+	case "serve":
+		if err := Serve(os.Stdin, os.Stdout); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	case "crash":
+		io.ReadAtLeast(os.Stdin, make([]byte, 1), 1)
+		os.Exit(2)
+	case "hang":
+		select {}
+	case "hog":
+		hog := make([]byte, 256<<20)
+		for i := range hog {
+			hog[i] = 1
+		}
+		select {}
+	}
+	os.Exit(m.Run())
+}
+
+func testPool(mode string, size int) *Pool {
+	return testPoolWithLimits(mode, size, Limits{})
+}
+
+func testPoolWithLimits(mode string, size int, limits Limits) *Pool {
+	return NewPool(Config{
+		Path:   os.Args[0],
+		Env:    append(os.Environ(), "ROBOTSTXT_TEST_WORKER="+mode),
+		Size:   size,
+		Limits: limits,
+	})
+}
+
+func TestPoolIsAllowed(t *testing.T) {
+	p := testPool("serve", 2)
+	defer p.Close()
+
+	robotsTxt := "User-agent: *\nCrawl-delay: 3\nDisallow: /private/\n"
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := p.IsAllowed(context.Background(), robotsTxt, "Googlebot", "https://example.com/private/x")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if res.Allowed || res.MatchingLine != 3 || res.CrawlDelay == nil || *res.CrawlDelay != 3 {
+				t.Errorf("Unexpected result %+v", res)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestPoolInvalidUTF8(t *testing.T) {
+	p := testPool("serve", 1)
+	defer p.Close()
+	m := robotstxt.NewMatcher()
+	defer m.Free()
+
+	robotsTxt := "User-agent: *\nDisallow: /\xff\n"
+	for _, url := range []string{"https://example.com/\xff", "https://example.com/\xfe"} {
+		res, err := p.IsAllowed(context.Background(), robotsTxt, "Googlebot", url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := m.IsAllowed(robotsTxt, "Googlebot", url); res.Allowed != want || res.MatchingLine != m.MatchingLine() {
+			t.Errorf("%q: worker %+v, in-process allowed %v line %d", url, res, want, m.MatchingLine())
+		}
+	}
+}
+
+func TestPoolCrash(t *testing.T) {
+	p := testPool("crash", 1)
+	defer p.Close()
+
+	for i := 0; i < 2; i++ {
+		_, err := p.IsAllowed(context.Background(), "User-agent: *\n", "Googlebot", "https://example.com/")
+		if !errors.Is(err, ErrCrashed) {
+			t.Fatalf("Expected ErrCrashed, got %v", err)
+		}
+	}
+}
+
+func TestPoolContext(t *testing.T) {
+	p := testPool("hang", 1)
+	defer p.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := p.IsAllowed(ctx, "", "Googlebot", "https://example.com/"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestPoolClosed(t *testing.T) {
+	p := testPool("serve", 1)
+	p.Close()
+	if _, err := p.IsAllowed(context.Background(), "", "Googlebot", "https://example.com/"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
+
+func TestPoolCloseConcurrent(t *testing.T) {
+	p := testPool("serve", 4)
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.IsAllowed(context.Background(), "User-agent: *\n", "Googlebot", "https://example.com/")
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	p.Close()
+	wg.Wait()
+	if idle, busy := len(p.idle), len(p.slots); idle != 0 || busy != 0 {
+		t.Errorf("After Close: %d idle and %d running workers, want none", idle, busy)
+	}
+}
+
+func TestLimitInputSize(t *testing.T) {
+	p := testPoolWithLimits("serve", 1, Limits{MaxInputSize: 16})
+	defer p.Close()
+
+	robotsTxt := "User-agent: *\n" + strings.Repeat("Disallow: /x\n", 10)
+	if _, err := p.IsAllowed(context.Background(), robotsTxt, "Googlebot", "https://example.com/"); !errors.Is(err, ErrResourceLimit) {
+		t.Errorf("Expected ErrResourceLimit, got %v", err)
+	}
+}
+
+func TestLimitDuration(t *testing.T) {
+	p := testPoolWithLimits("hang", 1, Limits{MaxDuration: 100 * time.Millisecond})
+	defer p.Close()
+
+	_, err := p.IsAllowed(context.Background(), "", "Googlebot", "https://example.com/")
+	if !errors.Is(err, ErrResourceLimit) || !errors.Is(err, ErrTimeout) {
+		t.Errorf("Expected ErrTimeout, got %v", err)
+	}
+}
+
+func TestIsAllowedTimeout(t *testing.T) {
+	p := testPoolWithLimits("hang", 1, Limits{MaxDuration: time.Minute})
+	defer p.Close()
+
+	start := time.Now()
+	_, err := p.IsAllowedTimeout(context.Background(), 50*time.Millisecond, "", "Googlebot", "https://example.com/")
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("Expected ErrTimeout, got %v", err)
+	}
+	if d := time.Since(start); d > 10*time.Second {
+		t.Errorf("Per-call timeout took %v", d)
+	}
+
+	p = testPool("serve", 1)
+	defer p.Close()
+	if res, err := p.IsAllowedTimeout(context.Background(), 10*time.Second, "User-agent: *\nDisallow: /\n", "Googlebot", "https://example.com/"); err != nil || res.Allowed {
+		t.Errorf("IsAllowedTimeout = %+v, %v", res, err)
+	}
+}
+
+func TestLimitMemory(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("MaxMemory is only enforced on Linux")
+	}
+	p := testPoolWithLimits("hog", 1, Limits{MaxMemory: 64 << 20, MaxDuration: 10 * time.Second})
+	defer p.Close()
+
+	_, err := p.IsAllowed(context.Background(), "", "Googlebot", "https://example.com/")
+	if !errors.Is(err, ErrResourceLimit) || !strings.Contains(err.Error(), "bytes") {
+		t.Errorf("Expected memory ErrResourceLimit, got %v", err)
+	}
+}
+
+func TestBackendError(t *testing.T) {
+	p := testPoolWithLimits("serve", 1, Limits{MaxInputSize: 1})
+	defer p.Close()
+
+	_, err := p.IsAllowed(context.Background(), "User-agent: *\n", "Googlebot", "https://example.com/")
+	var be *robotstxt.BackendError
+	if !errors.As(err, &be) || be.Backend != "worker" || !errors.Is(err, ErrResourceLimit) {
+		t.Errorf("Expected a worker BackendError, got %#v", err)
+	}
+}
@@ -509,6 +509,39 @@ class ParsedRobotsKey {
   std::string_view key_text_;
 };
 
+// Parses "HHMM" or "HH:MM" into minutes after midnight. Returns -1 on error.
+int ParseTimeOfDay(std::string_view s) {
+  std::string digits;
+  for (char c : s) {
+    if (c == ':' && digits.size() == 2) continue;
+    if (c < '0' || c > '9') return -1;
+    digits.push_back(c);
+  }
+  if (digits.size() != 4) return -1;
+  const int hours = (digits[0] - '0') * 10 + (digits[1] - '0');
+  const int minutes = (digits[2] - '0') * 10 + (digits[3] - '0');
+  if (hours > 24 || minutes > 59 || (hours == 24 && minutes != 0)) return -1;
+  return hours * 60 + minutes;
+}
+
+// Parses a visit window such as " 0600-1400". Leaves start and end untouched
+// unless the whole window is well-formed.
+void ParseTimeWindow(std::string_view s, int* start, int* end) {
+  while (!s.empty() && (s.front() == ' ' || s.front() == '\t')) {
+    s.remove_prefix(1);
+  }
+  while (!s.empty() && (s.back() == ' ' || s.back() == '\t')) {
+    s.remove_suffix(1);
+  }
+  const size_t dash = s.find('-');
+  if (dash == std::string_view::npos) return;
+  const int from = ParseTimeOfDay(s.substr(0, dash));
+  const int to = ParseTimeOfDay(s.substr(dash + 1));
+  if (from < 0 || to < 0) return;
+  *start = from;
+  *end = to;
+}
+
 void EmitKeyValueToHandler(int line, const ParsedRobotsKey& key,
                            std::string_view value,
                            RobotsParseHandler* handler) {
@@ -555,6 +588,12 @@ void EmitKeyValueToHandler(int line, const ParsedRobotsKey& key,
           }
           // If no "/" found, treat as "requests/1" (requests per second)
         }
+        // Optional visit window after the rate (e.g., "1/10 0600-1400").
+        const size_t space = value.find_first_of(" \t");
+        if (space != std::string_view::npos) {
+          ParseTimeWindow(value.substr(space), &rate.window_start,
+                          &rate.window_end);
+        }
       }
       handler->HandleRequestRate(line, rate);
       break;
@@ -935,6 +974,8 @@ void RobotsMatcher::HandleRobotsStart() {
 
   crawl_delay_global_.reset();
   crawl_delay_specific_.reset();
+  crawl_delay_global_line_ = 0;
+  crawl_delay_specific_line_ = 0;
   request_rate_global_.reset();
   request_rate_specific_.reset();
 #if ROBOTS_SUPPORT_CONTENT_SIGNAL
@@ -1068,10 +1109,12 @@ void RobotsMatcher::HandleCrawlDelay(int line_num, double value) {
     // Only store if not already set (first value wins within a group).
     if (!crawl_delay_specific_.has_value()) {
       crawl_delay_specific_ = value;
+      crawl_delay_specific_line_ = line_num;
     }
   } else if (seen_global_agent_) {
     if (!crawl_delay_global_.has_value()) {
       crawl_delay_global_ = value;
+      crawl_delay_global_line_ = line_num;
     }
   }
 }
@@ -1084,6 +1127,21 @@ std::optional<double> RobotsMatcher::GetCrawlDelay() const {
   return crawl_delay_global_;
 }
 
+RobotsMatcher::GroupSource RobotsMatcher::GetCrawlDelaySource() const {
+  if (ever_seen_specific_agent_ && crawl_delay_specific_.has_value()) {
+    return GroupSource::kSpecific;
+  }
+  return crawl_delay_global_.has_value() ? GroupSource::kGlobal
+                                         : GroupSource::kNone;
+}
+
+int RobotsMatcher::GetCrawlDelayLine() const {
+  if (ever_seen_specific_agent_ && crawl_delay_specific_.has_value()) {
+    return crawl_delay_specific_line_;
+  }
+  return crawl_delay_global_line_;
+}
+
 void RobotsMatcher::HandleRequestRate(int line_num, const RequestRate& rate) {
   if (!seen_any_agent()) return;
   // Store request-rate for the current user-agent group.
@@ -63,6 +63,15 @@ struct RequestRate {
   double DelaySeconds() const {
     return requests > 0 ? static_cast<double>(seconds) / requests : 0.0;
   }
+
+  // Optional visit window from "Request-rate: 1/10 0600-1400", as minutes
+  // after midnight UTC. Both are -1 when the directive has no window. An end
+  // earlier than the start means the window wraps past midnight.
+  int window_start = -1;
+  int window_end = -1;
+
+  // Returns true if the directive carried a valid time-of-day window.
+  bool HasWindow() const { return window_start >= 0 && window_end >= 0; }
 };
 
 #if ROBOTS_SUPPORT_CONTENT_SIGNAL
@@ -238,6 +247,18 @@ class RobotsMatcher : protected RobotsParseHandler {
   // crawlers may use it.
   std::optional<double> GetCrawlDelay() const;
 
+  // Identifies the user-agent group a per-group value was taken from.
+  enum class GroupSource { kNone, kSpecific, kGlobal };
+
+  // Returns the group that supplied the value returned by GetCrawlDelay().
+  // kGlobal together with ever_seen_specific_agent() means the matched group
+  // had no crawl-delay and the global (*) group's value was used instead.
+  GroupSource GetCrawlDelaySource() const;
+
+  // Returns the line number of the crawl-delay line that supplied the value
+  // returned by GetCrawlDelay(), or 0 if there is none.
+  int GetCrawlDelayLine() const;
+
   // Returns the request-rate for the matched user-agent.
   // Returns std::nullopt if no request-rate was specified.
   // Note: This is a non-standard directive that Google ignores.
@@ -361,6 +382,9 @@ class RobotsMatcher : protected RobotsParseHandler {
   // Uses std::optional to distinguish between "not set" and "set to 0".
   std::optional<double> crawl_delay_global_;
   std::optional<double> crawl_delay_specific_;
+  // Line numbers of the crawl-delay lines above, 0 if unset.
+  int crawl_delay_global_line_ = 0;
+  int crawl_delay_specific_line_ = 0;
 
   // Request-rate values for global (*) and specific user-agent groups.
   std::optional<RequestRate> request_rate_global_;
@@ -74,6 +74,15 @@ struct RequestRate {
   double DelaySeconds() const {
     return requests > 0 ? static_cast<double>(seconds) / requests : 0.0;
   }
+
+  // Optional visit window from "Request-rate: 1/10 0600-1400", as minutes
+  // after midnight UTC. Both are -1 when the directive has no window. An end
+  // earlier than the start means the window wraps past midnight.
+  int window_start = -1;
+  int window_end = -1;
+
+  // Returns true if the directive carried a valid time-of-day window.
+  bool HasWindow() const { return window_start >= 0 && window_end >= 0; }
 };
 
 #if ROBOTS_SUPPORT_CONTENT_SIGNAL
@@ -249,6 +258,18 @@ class RobotsMatcher : protected RobotsParseHandler {
   // crawlers may use it.
   std::optional<double> GetCrawlDelay() const;
 
+  // Identifies the user-agent group a per-group value was taken from.
+  enum class GroupSource { kNone, kSpecific, kGlobal };
+
+  // Returns the group that supplied the value returned by GetCrawlDelay().
+  // kGlobal together with ever_seen_specific_agent() means the matched group
+  // had no crawl-delay and the global (*) group's value was used instead.
+  GroupSource GetCrawlDelaySource() const;
+
+  // Returns the line number of the crawl-delay line that supplied the value
+  // returned by GetCrawlDelay(), or 0 if there is none.
+  int GetCrawlDelayLine() const;
+
   // Returns the request-rate for the matched user-agent.
   // Returns std::nullopt if no request-rate was specified.
   // Note: This is a non-standard directive that Google ignores.
@@ -372,6 +393,9 @@ class RobotsMatcher : protected RobotsParseHandler {
   // Uses std::optional to distinguish between "not set" and "set to 0".
   std::optional<double> crawl_delay_global_;
   std::optional<double> crawl_delay_specific_;
+  // Line numbers of the crawl-delay lines above, 0 if unset.
+  int crawl_delay_global_line_ = 0;
+  int crawl_delay_specific_line_ = 0;
 
   // Request-rate values for global (*) and specific user-agent groups.
   std::optional<RequestRate> request_rate_global_;
@@ -968,6 +992,39 @@ class ParsedRobotsKey {
   std::string_view key_text_;
 };
 
+// Parses "HHMM" or "HH:MM" into minutes after midnight. Returns -1 on error.
+int ParseTimeOfDay(std::string_view s) {
+  std::string digits;
+  for (char c : s) {
+    if (c == ':' && digits.size() == 2) continue;
+    if (c < '0' || c > '9') return -1;
+    digits.push_back(c);
+  }
+  if (digits.size() != 4) return -1;
+  const int hours = (digits[0] - '0') * 10 + (digits[1] - '0');
+  const int minutes = (digits[2] - '0') * 10 + (digits[3] - '0');
+  if (hours > 24 || minutes > 59 || (hours == 24 && minutes != 0)) return -1;
+  return hours * 60 + minutes;
+}
+
+// Parses a visit window such as " 0600-1400". Leaves start and end untouched
+// unless the whole window is well-formed.
+void ParseTimeWindow(std::string_view s, int* start, int* end) {
+  while (!s.empty() && (s.front() == ' ' || s.front() == '\t')) {
+    s.remove_prefix(1);
+  }
+  while (!s.empty() && (s.back() == ' ' || s.back() == '\t')) {
+    s.remove_suffix(1);
+  }
+  const size_t dash = s.find('-');
+  if (dash == std::string_view::npos) return;
+  const int from = ParseTimeOfDay(s.substr(0, dash));
+  const int to = ParseTimeOfDay(s.substr(dash + 1));
+  if (from < 0 || to < 0) return;
+  *start = from;
+  *end = to;
+}
+
 void EmitKeyValueToHandler(int line, const ParsedRobotsKey& key,
                            std::string_view value,
                            RobotsParseHandler* handler) {
@@ -1014,6 +1071,12 @@ void EmitKeyValueToHandler(int line, const ParsedRobotsKey& key,
           }
           // If no "/" found, treat as "requests/1" (requests per second)
         }
+        // Optional visit window after the rate (e.g., "1/10 0600-1400").
+        const size_t space = value.find_first_of(" \t");
+        if (space != std::string_view::npos) {
+          ParseTimeWindow(value.substr(space), &rate.window_start,
+                          &rate.window_end);
+        }
       }
       handler->HandleRequestRate(line, rate);
       break;
@@ -1394,6 +1457,8 @@ void RobotsMatcher::HandleRobotsStart() {
 
   crawl_delay_global_.reset();
   crawl_delay_specific_.reset();
+  crawl_delay_global_line_ = 0;
+  crawl_delay_specific_line_ = 0;
   request_rate_global_.reset();
   request_rate_specific_.reset();
 #if ROBOTS_SUPPORT_CONTENT_SIGNAL
@@ -1527,10 +1592,12 @@ void RobotsMatcher::HandleCrawlDelay(int line_num, double value) {
     // Only store if not already set (first value wins within a group).
     if (!crawl_delay_specific_.has_value()) {
       crawl_delay_specific_ = value;
+      crawl_delay_specific_line_ = line_num;
     }
   } else if (seen_global_agent_) {
     if (!crawl_delay_global_.has_value()) {
       crawl_delay_global_ = value;
+      crawl_delay_global_line_ = line_num;
     }
   }
 }
@@ -1543,6 +1610,21 @@ std::optional<double> RobotsMatcher::GetCrawlDelay() const {
   return crawl_delay_global_;
 }
 
+RobotsMatcher::GroupSource RobotsMatcher::GetCrawlDelaySource() const {
+  if (ever_seen_specific_agent_ && crawl_delay_specific_.has_value()) {
+    return GroupSource::kSpecific;
+  }
+  return crawl_delay_global_.has_value() ? GroupSource::kGlobal
+                                         : GroupSource::kNone;
+}
+
+int RobotsMatcher::GetCrawlDelayLine() const {
+  if (ever_seen_specific_agent_ && crawl_delay_specific_.has_value()) {
+    return crawl_delay_specific_line_;
+  }
+  return crawl_delay_global_line_;
+}
+
 void RobotsMatcher::HandleRequestRate(int line_num, const RequestRate& rate) {
   if (!seen_any_agent()) return;
   // Store request-rate for the current user-agent group.
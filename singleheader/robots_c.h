@@ -113,6 +113,15 @@ struct RequestRate {
   double DelaySeconds() const {
     return requests > 0 ? static_cast<double>(seconds) / requests : 0.0;
   }
+
+  // Optional visit window from "Request-rate: 1/10 0600-1400", as minutes
+  // after midnight UTC. Both are -1 when the directive has no window. An end
+  // earlier than the start means the window wraps past midnight.
+  int window_start = -1;
+  int window_end = -1;
+
+  // Returns true if the directive carried a valid time-of-day window.
+  bool HasWindow() const { return window_start >= 0 && window_end >= 0; }
 };
 
 #if ROBOTS_SUPPORT_CONTENT_SIGNAL
@@ -288,6 +297,18 @@ class RobotsMatcher : protected RobotsParseHandler {
   // crawlers may use it.
   std::optional<double> GetCrawlDelay() const;
 
+  // Identifies the user-agent group a per-group value was taken from.
+  enum class GroupSource { kNone, kSpecific, kGlobal };
+
+  // Returns the group that supplied the value returned by GetCrawlDelay().
+  // kGlobal together with ever_seen_specific_agent() means the matched group
+  // had no crawl-delay and the global (*) group's value was used instead.
+  GroupSource GetCrawlDelaySource() const;
+
+  // Returns the line number of the crawl-delay line that supplied the value
+  // returned by GetCrawlDelay(), or 0 if there is none.
+  int GetCrawlDelayLine() const;
+
   // Returns the request-rate for the matched user-agent.
   // Returns std::nullopt if no request-rate was specified.
   // Note: This is a non-standard directive that Google ignores.
@@ -411,6 +432,9 @@ class RobotsMatcher : protected RobotsParseHandler {
   // Uses std::optional to distinguish between "not set" and "set to 0".
   std::optional<double> crawl_delay_global_;
   std::optional<double> crawl_delay_specific_;
+  // Line numbers of the crawl-delay lines above, 0 if unset.
+  int crawl_delay_global_line_ = 0;
+  int crawl_delay_specific_line_ = 0;
 
   // Request-rate values for global (*) and specific user-agent groups.
   std::optional<RequestRate> request_rate_global_;
@@ -449,6 +473,13 @@ typedef struct {
   int seconds;   // Time period in seconds
 } robots_request_rate_t;
 
+// Time-of-day visit window from "Request-rate: 1/10 0600-1400", in minutes
+// after midnight UTC. end_minute < start_minute wraps past midnight.
+typedef struct {
+  int start_minute;
+  int end_minute;
+} robots_time_window_t;
+
 // Content-Signal values for AI content preferences.
 // Each field uses a tri-state: -1 = not set, 0 = no, 1 = yes.
 typedef struct {
@@ -457,6 +488,30 @@ typedef struct {
   int8_t search;    // search: Building search indexes and providing results
 } robots_content_signal_t;
 
+// User-agent group a per-group value (e.g. crawl-delay) was taken from.
+typedef enum {
+  ROBOTS_GROUP_NONE = 0,      // Value not specified
+  ROBOTS_GROUP_SPECIFIC = 1,  // Group matching the user-agent
+  ROBOTS_GROUP_GLOBAL = 2,    // Global (*) group
+} robots_group_source_t;
+
+// Result of robots_check(): the verdict together with all per-match state.
+typedef struct {
+  bool allowed;
+  int matching_line;                       // 0 if no rule matched
+  bool ever_seen_specific_agent;
+  bool has_crawl_delay;
+  double crawl_delay;                      // Seconds, valid if has_crawl_delay
+  robots_group_source_t crawl_delay_source;
+  int crawl_delay_line;
+  bool has_request_rate;
+  robots_request_rate_t request_rate;      // Valid if has_request_rate
+  bool has_request_rate_window;
+  robots_time_window_t request_rate_window;  // Valid if has_request_rate_window
+  bool has_content_signal;
+  robots_content_signal_t content_signal;  // Valid if has_content_signal
+} robots_check_result_t;
+
 // =============================================================================
 // Matcher lifecycle
 // =============================================================================
@@ -470,6 +525,11 @@ robots_matcher_t* robots_matcher_create(void);
 // Safe to call with NULL.
 void robots_matcher_free(robots_matcher_t* matcher);
 
+// Clears all per-match state (matching line, crawl-delay, request-rate and
+// content-signal) so the matcher behaves as if freshly created.
+// Safe to call with NULL.
+void robots_matcher_reset(robots_matcher_t* matcher);
+
 // =============================================================================
 // URL checking
 // =============================================================================
@@ -513,6 +573,18 @@ bool robots_allowed_by_robots_multi(
     size_t num_user_agents,
     const char* url, size_t url_len);
 
+// Checks if a URL is allowed for a single user-agent and fills in all
+// per-match state, replacing robots_allowed_by_robots() followed by the
+// individual accessors below.
+//
+// Returns true if the URL is allowed, false if disallowed. result may be NULL.
+bool robots_check(
+    robots_matcher_t* matcher,
+    const char* robots_txt, size_t robots_txt_len,
+    const char* user_agent, size_t user_agent_len,
+    const char* url, size_t url_len,
+    robots_check_result_t* result);
+
 // =============================================================================
 // Matcher state accessors (call after robots_allowed_by_robots)
 // =============================================================================
@@ -534,6 +606,16 @@ bool robots_has_crawl_delay(const robots_matcher_t* matcher);
 // Call robots_has_crawl_delay() first to distinguish "not set" from "0".
 double robots_get_crawl_delay(const robots_matcher_t* matcher);
 
+// Returns the group that supplied the crawl-delay. ROBOTS_GROUP_GLOBAL with
+// robots_ever_seen_specific_agent() means the matched group had no
+// crawl-delay and the global (*) group's value was used instead.
+robots_group_source_t robots_crawl_delay_source(
+    const robots_matcher_t* matcher);
+
+// Returns the line number of the crawl-delay line that supplied the value, or
+// 0 if none was specified.
+int robots_crawl_delay_line(const robots_matcher_t* matcher);
+
 // =============================================================================
 // Request-rate support (non-standard directive)
 // =============================================================================
@@ -546,6 +628,11 @@ bool robots_has_request_rate(const robots_matcher_t* matcher);
 bool robots_get_request_rate(const robots_matcher_t* matcher,
                               robots_request_rate_t* rate);
 
+// Gets the visit window of the request-rate, if it carried one. Returns false
+// if no request-rate or no window was specified.
+bool robots_get_request_rate_window(const robots_matcher_t* matcher,
+                                         robots_time_window_t* window);
+
 // =============================================================================
 // Content-Signal support (proposed AI directive)
 // =============================================================================
@@ -567,6 +654,83 @@ bool robots_allows_ai_train(const robots_matcher_t* matcher);
 bool robots_allows_ai_input(const robots_matcher_t* matcher);
 bool robots_allows_search(const robots_matcher_t* matcher);
 
+// =============================================================================
+// Parsing
+// =============================================================================
+
+// Directive types reported by robots_parse().
+typedef enum {
+  ROBOTS_DIRECTIVE_UNKNOWN = 0,
+  ROBOTS_DIRECTIVE_USER_AGENT = 1,
+  ROBOTS_DIRECTIVE_ALLOW = 2,
+  ROBOTS_DIRECTIVE_DISALLOW = 3,
+  ROBOTS_DIRECTIVE_SITEMAP = 4,
+  ROBOTS_DIRECTIVE_CRAWL_DELAY = 5,
+  ROBOTS_DIRECTIVE_REQUEST_RATE = 6,
+  ROBOTS_DIRECTIVE_CONTENT_SIGNAL = 7
+} robots_directive_type_t;
+
+// One directive line. Strings are not null-terminated and remain valid until
+// robots_parsed_free(). key is only set for ROBOTS_DIRECTIVE_UNKNOWN; value
+// is empty for crawl-delay, request-rate and content-signal, which the parser
+// reports pre-parsed (use the matcher accessors for those).
+typedef struct {
+  int line;
+  robots_directive_type_t type;
+  const char* key;
+  size_t key_len;
+  const char* value;
+  size_t value_len;
+} robots_directive_t;
+
+// Line statistics of a parse result.
+typedef struct {
+  int lines;           // Number of lines, as numbered by the parser
+  int long_lines;      // Lines over the parser's length limit, whose tail
+                       // was ignored
+} robots_parse_info_t;
+
+// Opaque parse result.
+typedef struct robots_parsed_s robots_parsed_t;
+
+// Parses robots.txt and records its directives in file order, with the same
+// key recognition (including accepted typos) as the matcher. Returns NULL on
+// allocation failure. The result must be freed with robots_parsed_free().
+robots_parsed_t* robots_parse(const char* robots_txt, size_t robots_txt_len);
+
+// Frees a parse result. Safe to call with NULL.
+void robots_parsed_free(robots_parsed_t* parsed);
+
+// Fills in line statistics for a parse result.
+void robots_parsed_info(const robots_parsed_t* parsed,
+                             robots_parse_info_t* info);
+
+// Returns the number of directives in a parse result.
+size_t robots_parsed_count(const robots_parsed_t* parsed);
+
+// Returns the directives of a parse result (robots_parsed_count() entries).
+const robots_directive_t* robots_parsed_directives(
+    const robots_parsed_t* parsed);
+
+// =============================================================================
+// Matched rule
+// =============================================================================
+
+// The allow or disallow rule that decided the most recent match.
+typedef struct {
+  int line;
+  robots_directive_type_t type;  // ROBOTS_DIRECTIVE_ALLOW or _DISALLOW
+  const char* pattern;           // Not null-terminated; valid until the
+  size_t pattern_len;            // next match or robots_matcher_free()
+} robots_matched_rule_t;
+
+// Fills in the rule on robots_matching_line(), with its pattern as the
+// matcher saw it (percent-escaped), so callers need not keep the robots.txt
+// body to explain a verdict. Returns false and leaves rule untouched if no
+// rule matched.
+bool robots_matched_rule(const robots_matcher_t* matcher,
+                             robots_matched_rule_t* rule);
+
 // =============================================================================
 // Utility functions
 // =============================================================================
@@ -577,6 +741,29 @@ bool robots_is_valid_user_agent(const char* user_agent, size_t len);
 // Returns the library version string.
 const char* robots_version(void);
 
+// Version of the C ABI: the layout of the structs and the signatures of the
+// functions in this header. It changes whenever either changes
+// incompatibly, so bindings can refuse a mismatched shared library.
+#define ROBOTS_ABI_VERSION 1
+
+// Returns the ABI version the library was built with (ROBOTS_ABI_VERSION
+// of its header). Compare it with ROBOTS_ABI_VERSION before other calls.
+int robots_abi_version(void);
+
+// How the library was compiled, for bug reports and cache compatibility
+// checks. Strings are static and never NULL.
+typedef struct {
+  const char* version;   // Same as robots_version()
+  const char* commit;    // Source commit (ROBOTS_COMMIT), or "" if unknown
+  const char* compiler;  // Compiler name and version
+  long cplusplus;        // C++ standard (__cplusplus) the library was built with
+  bool content_signal;   // Built with ROBOTS_SUPPORT_CONTENT_SIGNAL
+  bool ada;              // URLs parsed with the ada library (ROBOTS_USE_ADA)
+} robots_build_info_t;
+
+// Fills in how the library was built.
+void robots_build_info(robots_build_info_t* info);
+
 #ifdef __cplusplus
 }
 #endif
@@ -1086,6 +1273,39 @@ class ParsedRobotsKey {
   std::string_view key_text_;
 };
 
+// Parses "HHMM" or "HH:MM" into minutes after midnight. Returns -1 on error.
+int ParseTimeOfDay(std::string_view s) {
+  std::string digits;
+  for (char c : s) {
+    if (c == ':' && digits.size() == 2) continue;
+    if (c < '0' || c > '9') return -1;
+    digits.push_back(c);
+  }
+  if (digits.size() != 4) return -1;
+  const int hours = (digits[0] - '0') * 10 + (digits[1] - '0');
+  const int minutes = (digits[2] - '0') * 10 + (digits[3] - '0');
+  if (hours > 24 || minutes > 59 || (hours == 24 && minutes != 0)) return -1;
+  return hours * 60 + minutes;
+}
+
+// Parses a visit window such as " 0600-1400". Leaves start and end untouched
+// unless the whole window is well-formed.
+void ParseTimeWindow(std::string_view s, int* start, int* end) {
+  while (!s.empty() && (s.front() == ' ' || s.front() == '\t')) {
+    s.remove_prefix(1);
+  }
+  while (!s.empty() && (s.back() == ' ' || s.back() == '\t')) {
+    s.remove_suffix(1);
+  }
+  const size_t dash = s.find('-');
+  if (dash == std::string_view::npos) return;
+  const int from = ParseTimeOfDay(s.substr(0, dash));
+  const int to = ParseTimeOfDay(s.substr(dash + 1));
+  if (from < 0 || to < 0) return;
+  *start = from;
+  *end = to;
+}
+
 void EmitKeyValueToHandler(int line, const ParsedRobotsKey& key,
                            std::string_view value,
                            RobotsParseHandler* handler) {
@@ -1132,6 +1352,12 @@ void EmitKeyValueToHandler(int line, const ParsedRobotsKey& key,
           }
           // If no "/" found, treat as "requests/1" (requests per second)
         }
+        // Optional visit window after the rate (e.g., "1/10 0600-1400").
+        const size_t space = value.find_first_of(" \t");
+        if (space != std::string_view::npos) {
+          ParseTimeWindow(value.substr(space), &rate.window_start,
+                          &rate.window_end);
+        }
       }
       handler->HandleRequestRate(line, rate);
       break;
@@ -1512,6 +1738,8 @@ void RobotsMatcher::HandleRobotsStart() {
 
   crawl_delay_global_.reset();
   crawl_delay_specific_.reset();
+  crawl_delay_global_line_ = 0;
+  crawl_delay_specific_line_ = 0;
   request_rate_global_.reset();
   request_rate_specific_.reset();
 #if ROBOTS_SUPPORT_CONTENT_SIGNAL
@@ -1645,10 +1873,12 @@ void RobotsMatcher::HandleCrawlDelay(int line_num, double value) {
     // Only store if not already set (first value wins within a group).
     if (!crawl_delay_specific_.has_value()) {
       crawl_delay_specific_ = value;
+      crawl_delay_specific_line_ = line_num;
     }
   } else if (seen_global_agent_) {
     if (!crawl_delay_global_.has_value()) {
       crawl_delay_global_ = value;
+      crawl_delay_global_line_ = line_num;
     }
   }
 }
@@ -1661,6 +1891,21 @@ std::optional<double> RobotsMatcher::GetCrawlDelay() const {
   return crawl_delay_global_;
 }
 
+RobotsMatcher::GroupSource RobotsMatcher::GetCrawlDelaySource() const {
+  if (ever_seen_specific_agent_ && crawl_delay_specific_.has_value()) {
+    return GroupSource::kSpecific;
+  }
+  return crawl_delay_global_.has_value() ? GroupSource::kGlobal
+                                         : GroupSource::kNone;
+}
+
+int RobotsMatcher::GetCrawlDelayLine() const {
+  if (ever_seen_specific_agent_ && crawl_delay_specific_.has_value()) {
+    return crawl_delay_specific_line_;
+  }
+  return crawl_delay_global_line_;
+}
+
 void RobotsMatcher::HandleRequestRate(int line_num, const RequestRate& rate) {
   if (!seen_any_agent()) return;
   // Store request-rate for the current user-agent group.
@@ -1812,12 +2057,76 @@ bool ParsedRobotsKey::KeyIsContentSignal(std::string_view key,
 
 #define ROBOTS_VERSION "1.1.0"
 
+// Source commit, set by the build (e.g. -DROBOTS_COMMIT="\"abc123\"").
+#ifndef ROBOTS_COMMIT
+#define ROBOTS_COMMIT ""
+#endif
+
+#define ROBOTS_STR_(x) #x
+#define ROBOTS_STR(x) ROBOTS_STR_(x)
+
+#if defined(__clang__)
+#define ROBOTS_COMPILER "clang " __clang_version__
+#elif defined(__GNUC__)
+#define ROBOTS_COMPILER "gcc " __VERSION__
+#elif defined(_MSC_VER)
+#define ROBOTS_COMPILER "msvc " ROBOTS_STR(_MSC_FULL_VER)
+#else
+#define ROBOTS_COMPILER "unknown"
+#endif
+
 // =============================================================================
 // Internal wrapper struct
 // =============================================================================
 
+// RobotsMatcher that keeps the pattern of each rule that became the best
+// match while matching, so the winning rule can be reported afterwards.
+class RuleRecordingMatcher : public googlebot::RobotsMatcher {
+ public:
+  struct Rule {
+    int line;
+    robots_directive_type_t type;
+    std::string pattern;
+  };
+
+  // Returns the rule on matching_line(), or nullptr if none matched.
+  const Rule* matched_rule() const {
+    int line = matching_line();
+    for (const auto& r : rules_) {
+      if (r.line == line) return &r;
+    }
+    return nullptr;
+  }
+
+ protected:
+  void HandleRobotsStart() override {
+    rules_.clear();
+    googlebot::RobotsMatcher::HandleRobotsStart();
+  }
+  void HandleAllow(int line_num, std::string_view value) override {
+    googlebot::RobotsMatcher::HandleAllow(line_num, value);
+    Record(line_num, ROBOTS_DIRECTIVE_ALLOW, value);
+  }
+  void HandleDisallow(int line_num, std::string_view value) override {
+    googlebot::RobotsMatcher::HandleDisallow(line_num, value);
+    Record(line_num, ROBOTS_DIRECTIVE_DISALLOW, value);
+  }
+
+ private:
+  // Only rules that just became the best match are kept; the final match
+  // is always among them.
+  void Record(int line_num, robots_directive_type_t type,
+              std::string_view value) {
+    if (matching_line() == line_num) {
+      rules_.push_back({line_num, type, std::string(value)});
+    }
+  }
+
+  std::vector<Rule> rules_;
+};
+
 struct robots_matcher_s {
-  googlebot::RobotsMatcher matcher;
+  RuleRecordingMatcher matcher;
 };
 
 // =============================================================================
@@ -1836,6 +2145,13 @@ extern "C" void robots_matcher_free(robots_matcher_t* matcher) {
   delete matcher;
 }
 
+extern "C" void robots_matcher_reset(robots_matcher_t* matcher) {
+  if (!matcher) return;
+  // Matching against an empty robots.txt runs HandleRobotsStart(), which
+  // clears all per-match state.
+  matcher->matcher.OneAgentAllowedByRobots("", "", "/");
+}
+
 // =============================================================================
 // URL checking
 // =============================================================================
@@ -1877,6 +2193,33 @@ extern "C" bool robots_allowed_by_robots_multi(
   return matcher->matcher.AllowedByRobots(robots_body, &agents, target_url);
 }
 
+extern "C" bool robots_check(
+    robots_matcher_t* matcher,
+    const char* robots_txt, size_t robots_txt_len,
+    const char* user_agent, size_t user_agent_len,
+    const char* url, size_t url_len,
+    robots_check_result_t* result) {
+  bool allowed = robots_allowed_by_robots(matcher, robots_txt, robots_txt_len,
+                                          user_agent, user_agent_len,
+                                          url, url_len);
+  if (!result) return allowed;
+
+  result->allowed = allowed;
+  result->matching_line = robots_matching_line(matcher);
+  result->ever_seen_specific_agent = robots_ever_seen_specific_agent(matcher);
+  result->has_crawl_delay = robots_has_crawl_delay(matcher);
+  result->crawl_delay = robots_get_crawl_delay(matcher);
+  result->crawl_delay_source = robots_crawl_delay_source(matcher);
+  result->crawl_delay_line = robots_crawl_delay_line(matcher);
+  result->has_request_rate =
+      robots_get_request_rate(matcher, &result->request_rate);
+  result->has_request_rate_window =
+      robots_get_request_rate_window(matcher, &result->request_rate_window);
+  result->has_content_signal =
+      robots_get_content_signal(matcher, &result->content_signal);
+  return allowed;
+}
+
 // =============================================================================
 // Matcher state accessors
 // =============================================================================
@@ -1906,6 +2249,24 @@ extern "C" double robots_get_crawl_delay(const robots_matcher_t* matcher) {
   return delay.value_or(0.0);
 }
 
+extern "C" robots_group_source_t robots_crawl_delay_source(
+    const robots_matcher_t* matcher) {
+  if (!matcher) return ROBOTS_GROUP_NONE;
+  switch (matcher->matcher.GetCrawlDelaySource()) {
+    case googlebot::RobotsMatcher::GroupSource::kSpecific:
+      return ROBOTS_GROUP_SPECIFIC;
+    case googlebot::RobotsMatcher::GroupSource::kGlobal:
+      return ROBOTS_GROUP_GLOBAL;
+    default:
+      return ROBOTS_GROUP_NONE;
+  }
+}
+
+extern "C" int robots_crawl_delay_line(const robots_matcher_t* matcher) {
+  if (!matcher) return 0;
+  return matcher->matcher.GetCrawlDelayLine();
+}
+
 // =============================================================================
 // Request-rate support
 // =============================================================================
@@ -1925,6 +2286,16 @@ extern "C" bool robots_get_request_rate(const robots_matcher_t* matcher,
   return true;
 }
 
+extern "C" bool robots_get_request_rate_window(const robots_matcher_t* matcher,
+                                               robots_time_window_t* window) {
+  if (!matcher || !window) return false;
+  auto opt_rate = matcher->matcher.GetRequestRate();
+  if (!opt_rate.has_value() || !opt_rate->HasWindow()) return false;
+  window->start_minute = opt_rate->window_start;
+  window->end_minute = opt_rate->window_end;
+  return true;
+}
+
 // =============================================================================
 // Content-Signal support
 // =============================================================================
@@ -2007,6 +2378,132 @@ extern "C" bool robots_allows_search(const robots_matcher_t* matcher) {
 #endif
 }
 
+// =============================================================================
+// Parsing
+// =============================================================================
+
+struct robots_parsed_s {
+  struct Entry {
+    int line;
+    robots_directive_type_t type;
+    std::string key;
+    std::string value;
+  };
+  std::vector<Entry> entries;
+  std::vector<robots_directive_t> directives;
+  robots_parse_info_t info = {0, 0};
+};
+
+namespace {
+
+// Records every directive reported by the parser.
+class DirectiveCollector : public googlebot::RobotsParseHandler {
+ public:
+  explicit DirectiveCollector(robots_parsed_t* parsed) : parsed_(parsed) {}
+
+  void HandleRobotsStart() override {}
+  void HandleRobotsEnd() override {}
+  void HandleUserAgent(int line_num, std::string_view value) override {
+    Add(line_num, ROBOTS_DIRECTIVE_USER_AGENT, {}, value);
+  }
+  void HandleAllow(int line_num, std::string_view value) override {
+    Add(line_num, ROBOTS_DIRECTIVE_ALLOW, {}, value);
+  }
+  void HandleDisallow(int line_num, std::string_view value) override {
+    Add(line_num, ROBOTS_DIRECTIVE_DISALLOW, {}, value);
+  }
+  void HandleSitemap(int line_num, std::string_view value) override {
+    Add(line_num, ROBOTS_DIRECTIVE_SITEMAP, {}, value);
+  }
+  void HandleCrawlDelay(int line_num, double) override {
+    Add(line_num, ROBOTS_DIRECTIVE_CRAWL_DELAY, {}, {});
+  }
+  void HandleRequestRate(int line_num, const googlebot::RequestRate&) override {
+    Add(line_num, ROBOTS_DIRECTIVE_REQUEST_RATE, {}, {});
+  }
+#if ROBOTS_SUPPORT_CONTENT_SIGNAL
+  void HandleContentSignal(int line_num,
+                           const googlebot::ContentSignal&) override {
+    Add(line_num, ROBOTS_DIRECTIVE_CONTENT_SIGNAL, {}, {});
+  }
+#endif  // ROBOTS_SUPPORT_CONTENT_SIGNAL
+  void HandleUnknownAction(int line_num, std::string_view action,
+                           std::string_view value) override {
+    Add(line_num, ROBOTS_DIRECTIVE_UNKNOWN, action, value);
+  }
+  void ReportLineMetadata(int line_num, const LineMetadata& metadata) override {
+    if (line_num > parsed_->info.lines) parsed_->info.lines = line_num;
+    if (metadata.is_line_too_long) ++parsed_->info.long_lines;
+  }
+
+ private:
+  void Add(int line_num, robots_directive_type_t type, std::string_view key,
+           std::string_view value) {
+    parsed_->entries.push_back(
+        {line_num, type, std::string(key), std::string(value)});
+  }
+
+  robots_parsed_t* parsed_;
+};
+
+}  // namespace
+
+extern "C" robots_parsed_t* robots_parse(const char* robots_txt,
+                                         size_t robots_txt_len) {
+  try {
+    auto* parsed = new robots_parsed_t();
+    DirectiveCollector collector(parsed);
+    googlebot::ParseRobotsTxt(
+        robots_txt ? std::string_view(robots_txt, robots_txt_len)
+                   : std::string_view(),
+        &collector);
+    // Entries no longer move, so their strings can be referenced directly.
+    parsed->directives.reserve(parsed->entries.size());
+    for (const auto& e : parsed->entries) {
+      parsed->directives.push_back({e.line, e.type, e.key.data(), e.key.size(),
+                                    e.value.data(), e.value.size()});
+    }
+    return parsed;
+  } catch (...) {
+    return nullptr;
+  }
+}
+
+extern "C" void robots_parsed_free(robots_parsed_t* parsed) {
+  delete parsed;
+}
+
+extern "C" void robots_parsed_info(const robots_parsed_t* parsed,
+                                   robots_parse_info_t* info) {
+  if (!info) return;
+  *info = parsed ? parsed->info : robots_parse_info_t{0, 0};
+}
+
+extern "C" size_t robots_parsed_count(const robots_parsed_t* parsed) {
+  return parsed ? parsed->directives.size() : 0;
+}
+
+extern "C" const robots_directive_t* robots_parsed_directives(
+    const robots_parsed_t* parsed) {
+  return parsed ? parsed->directives.data() : nullptr;
+}
+
+// =============================================================================
+// Matched rule
+// =============================================================================
+
+extern "C" bool robots_matched_rule(const robots_matcher_t* matcher,
+                                    robots_matched_rule_t* rule) {
+  if (!matcher || !rule) return false;
+  const auto* r = matcher->matcher.matched_rule();
+  if (!r) return false;
+  rule->line = r->line;
+  rule->type = r->type;
+  rule->pattern = r->pattern.data();
+  rule->pattern_len = r->pattern.size();
+  return true;
+}
+
 // =============================================================================
 // Utility functions
 // =============================================================================
@@ -2021,6 +2518,24 @@ extern "C" const char* robots_version(void) {
   return ROBOTS_VERSION;
 }
 
+extern "C" int robots_abi_version(void) {
+  return ROBOTS_ABI_VERSION;
+}
+
+extern "C" void robots_build_info(robots_build_info_t* info) {
+  if (!info) return;
+  info->version = ROBOTS_VERSION;
+  info->commit = ROBOTS_COMMIT;
+  info->compiler = ROBOTS_COMPILER;
+  info->cplusplus = __cplusplus;
+  info->content_signal = ROBOTS_SUPPORT_CONTENT_SIGNAL != 0;
+#ifdef ROBOTS_USE_ADA
+  info->ada = true;
+#else
+  info->ada = false;
+#endif
+}
+
 // === End robots_c.cc implementation ===
 
 #endif  // ROBOTS_IMPLEMENTATION && __cplusplus
@@ -243,6 +243,36 @@ TEST(RobotsUnittest, ID_CrawlDelay) {
     ASSERT_TRUE(delay.has_value());
     EXPECT_DOUBLE_EQ(5.0, delay.value());
   }
+  // Test GetCrawlDelaySource() reports which group supplied the value.
+  {
+    const std::string_view robotstxt =
+        "User-agent: *\n"
+        "Crawl-delay: 10\n"
+        "\n"
+        "User-agent: FooBot\n"
+        "Crawl-delay: 5\n"
+        "\n"
+        "User-agent: BarBot\n"
+        "Disallow: /private/\n";
+    using GroupSource = googlebot::RobotsMatcher::GroupSource;
+    googlebot::RobotsMatcher matcher;
+    std::vector<std::string> foo = {"FooBot"};
+    matcher.AllowedByRobots(robotstxt, &foo, "http://example.com/");
+    EXPECT_EQ(GroupSource::kSpecific, matcher.GetCrawlDelaySource());
+    EXPECT_EQ(5, matcher.GetCrawlDelayLine());
+
+    // BarBot's group has no crawl-delay, so the global value is used.
+    std::vector<std::string> bar = {"BarBot"};
+    matcher.AllowedByRobots(robotstxt, &bar, "http://example.com/");
+    EXPECT_EQ(GroupSource::kGlobal, matcher.GetCrawlDelaySource());
+    EXPECT_EQ(2, matcher.GetCrawlDelayLine());
+    EXPECT_TRUE(matcher.ever_seen_specific_agent());
+
+    matcher.AllowedByRobots("User-agent: *\nDisallow: /\n", &bar,
+                            "http://example.com/");
+    EXPECT_EQ(GroupSource::kNone, matcher.GetCrawlDelaySource());
+    EXPECT_EQ(0, matcher.GetCrawlDelayLine());
+  }
   // Test no crawl-delay returns nullopt.
   {
     const std::string_view robotstxt =
@@ -377,6 +407,38 @@ TEST(RobotsUnittest, ID_RequestRate) {
     ASSERT_TRUE(rate.has_value());
     EXPECT_EQ(2, rate.value().requests);
     EXPECT_EQ(1, rate.value().seconds);
+    EXPECT_FALSE(rate.value().HasWindow());
+  }
+
+  // Test request-rate with a time-of-day visit window.
+  {
+    const std::string_view robotstxt =
+        "User-agent: *\n"
+        "Request-rate: 1/10s 0600-1400\n";
+    googlebot::RobotsMatcher matcher;
+    std::vector<std::string> agents = {"Googlebot"};
+    matcher.AllowedByRobots(robotstxt, &agents, "http://example.com/");
+    auto rate = matcher.GetRequestRate();
+    ASSERT_TRUE(rate.has_value());
+    EXPECT_EQ(1, rate.value().requests);
+    EXPECT_EQ(10, rate.value().seconds);
+    ASSERT_TRUE(rate.value().HasWindow());
+    EXPECT_EQ(6 * 60, rate.value().window_start);
+    EXPECT_EQ(14 * 60, rate.value().window_end);
+  }
+
+  // Test that a malformed window is ignored but the rate is kept.
+  {
+    const std::string_view robotstxt =
+        "User-agent: *\n"
+        "Request-rate: 1/10 2500-0100\n";
+    googlebot::RobotsMatcher matcher;
+    std::vector<std::string> agents = {"Googlebot"};
+    matcher.AllowedByRobots(robotstxt, &agents, "http://example.com/");
+    auto rate = matcher.GetRequestRate();
+    ASSERT_TRUE(rate.has_value());
+    EXPECT_EQ(10, rate.value().seconds);
+    EXPECT_FALSE(rate.value().HasWindow());
   }
 }
 